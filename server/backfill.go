@@ -0,0 +1,64 @@
+//go:build backfill
+
+package main
+
+import (
+	"log"
+	"riddles-server/config"
+	"riddles-server/database"
+	"riddles-server/repository"
+	"riddles-server/services"
+	"riddles-server/storage"
+)
+
+// Gives slugs to categories created before slugs existed, evaluates every
+// achievement against existing progress history and awards the ones users
+// already qualify for, then queues failed and revealed riddles for review
+func main() {
+	// Initialize database
+	database.ConnectDB()
+	database.MigrateDB()
+
+	slugs, err := services.NewCategoryService(repository.NewCategoryRepository()).AssignSlugs()
+	if err != nil {
+		log.Fatal("Failed to assign category slugs:", err)
+	}
+	log.Printf("Assigned slugs to %d categories", slugs)
+
+	userRepo := repository.NewUserRepository()
+	achievementService := services.NewAchievementService(
+		repository.NewAchievementRepository(),
+		repository.NewStreakRepository(),
+	)
+	blobStore, err := storage.NewBlobStore(config.LoadConfig())
+	if err != nil {
+		log.Fatal("Failed to set up media storage:", err)
+	}
+	reviewService := services.NewReviewService(
+		repository.NewReviewRepository(),
+		repository.NewProgressRepository(),
+		services.NewMediaService(repository.NewMediaRepository(), repository.NewRiddleRepository(), blobStore),
+	)
+
+	userIDs, err := userRepo.FindAllIDs()
+	if err != nil {
+		log.Fatal("Failed to load users:", err)
+	}
+
+	awarded, queued := 0, 0
+	for _, userID := range userIDs {
+		count, err := achievementService.Evaluate(userID)
+		if err != nil {
+			log.Printf("Error evaluating achievements for user %d: %v", userID, err)
+		}
+		awarded += count
+
+		count, err = reviewService.Backfill(userID)
+		if err != nil {
+			log.Printf("Error queueing reviews for user %d: %v", userID, err)
+		}
+		queued += count
+	}
+
+	log.Printf("Backfill completed for %d users: %d achievements awarded, %d riddles queued for review", len(userIDs), awarded, queued)
+}
\ No newline at end of file
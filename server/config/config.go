@@ -5,22 +5,41 @@ import (
 )
 
 type Config struct {
-	DBHost     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	DBPort     string
-	JWTSecret  string
+	DBHost       string
+	DBUser       string
+	DBPassword   string
+	DBName       string
+	DBPort       string
+	JWTSecret    string
+	GameTimezone string // IANA zone in which game days (daily riddles, streaks) start
+	PublicURL    string // base URL used in links handed out to users
+	// Media storage: "local" keeps files in MediaDir, "s3" uses an S3-compatible bucket
+	MediaStore  string
+	MediaDir    string
+	S3Endpoint  string // e.g. http://localhost:9000 for MinIO
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
 }
 
 func LoadConfig() *Config {
 	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "admin"),
-		DBName:     getEnv("DB_NAME", "riddles"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		JWTSecret:  getEnv("JWT_SECRET", "riddles_secret_key"),
+		DBHost:       getEnv("DB_HOST", "localhost"),
+		DBUser:       getEnv("DB_USER", "postgres"),
+		DBPassword:   getEnv("DB_PASSWORD", "admin"),
+		DBName:       getEnv("DB_NAME", "riddles"),
+		DBPort:       getEnv("DB_PORT", "5432"),
+		JWTSecret:    getEnv("JWT_SECRET", "riddles_secret_key"),
+		GameTimezone: getEnv("GAME_TIMEZONE", "Europe/Moscow"),
+		PublicURL:    getEnv("PUBLIC_URL", "http://localhost:8080"),
+		MediaStore:   getEnv("MEDIA_STORE", "local"),
+		MediaDir:     getEnv("MEDIA_DIR", "media"),
+		S3Endpoint:   getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3Region:     getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:     getEnv("S3_BUCKET", "riddles-media"),
+		S3AccessKey:  getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:  getEnv("S3_SECRET_KEY", ""),
 	}
 }
 
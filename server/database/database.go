@@ -32,10 +32,41 @@ func MigrateDB() {
 		&models.User{},
 		&models.Riddle{},
 		&models.Category{},
+		&models.Tag{},
 		&models.UserRiddleProgress{},
 		&models.Favorite{},
 		&models.RiddleRating{},
 		&models.DailyRiddle{},
+		&models.UserStreak{},
+		&models.PointsEntry{},
+		&models.LeaderboardScore{},
+		&models.LeaderboardDay{},
+		&models.DailySpeedScore{},
+		&models.UserAchievement{},
+		&models.QuizSession{},
+		&models.QuizQuestion{},
+		&models.Team{},
+		&models.TeamMember{},
+		&models.ChgkGame{},
+		&models.ChgkGameQuestion{},
+		&models.ChgkGameTeam{},
+		&models.ChgkAnswer{},
+		&models.Duel{},
+		&models.DuelRiddle{},
+		&models.DuelPlay{},
+		&models.DuelAnswer{},
+		&models.ReviewItem{},
+		&models.PlacementTest{},
+		&models.PlacementStep{},
+		&models.Recommendation{},
+		&models.RiddleSubmission{},
+		&models.Comment{},
+		&models.ReportResolution{},
+		&models.RiddleReport{},
+		&models.Notification{},
+		&models.WrongAnswer{},
+		&models.RiddleRevision{},
+		&models.RiddleMedia{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
@@ -47,7 +47,7 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	}
 
 	// Generate tokens
-	accessToken, refreshToken, err := h.authService.Login(req.Email, req.Password)
+	_, accessToken, refreshToken, err := h.authService.Login(req.Email, req.Password)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate tokens")
 	}
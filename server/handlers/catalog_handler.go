@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"riddles-server/models"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Columns of the CSV format; list values are separated by csvListSeparator,
+// and the payload and key of typed riddles are JSON
+var catalogCSVHeader = []string{"title", "description", "answer", "accepted_answers", "category", "difficulty", "tags", "type", "payload", "key", "explanation"}
+
+const csvListSeparator = ";"
+
+type CatalogHandler struct {
+	catalogService services.CatalogService
+}
+
+func NewCatalogHandler(catalogService services.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// Export downloads every riddle as JSON, or as CSV with format=csv
+func (h *CatalogHandler) Export(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Format must be json or csv")
+	}
+
+	records, err := h.catalogService.Export()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export riddles")
+	}
+
+	if format != "csv" {
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="riddles.json"`)
+		return c.JSON(http.StatusOK, records)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="riddles.csv"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	if err := writer.Write(catalogCSVHeader); err != nil {
+		return err
+	}
+	for _, record := range records {
+		var payload, key string
+		if record.Type != models.RiddleText {
+			payload, key = marshalCSVJSON(record.Payload), marshalCSVJSON(record.Key)
+		}
+		err := writer.Write([]string{
+			record.Title,
+			record.Description,
+			record.Answer,
+			strings.Join(record.AcceptedAnswers, csvListSeparator),
+			record.Category,
+			record.Difficulty,
+			strings.Join(record.Tags, csvListSeparator),
+			record.Type,
+			payload,
+			key,
+			record.Explanation,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Import creates riddles from a JSON array or, with a text/csv content type,
+// from CSV in the export layout
+func (h *CatalogHandler) Import(c echo.Context) error {
+	var records []services.RiddleRecord
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/csv") {
+		var err error
+		records, err = readCatalogCSV(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid CSV: "+err.Error())
+		}
+	} else if err := c.Bind(&records); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	result, err := h.catalogService.Import(records)
+	switch {
+	case errors.Is(err, services.ErrImportInvalid):
+		return c.JSON(http.StatusUnprocessableEntity, result)
+	case errors.Is(err, services.ErrImportEmpty), errors.Is(err, services.ErrImportTooLarge):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import riddles")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// readCatalogCSV reads records by header name, so columns may come in any order
+func readCatalogCSV(body io.Reader) ([]services.RiddleRecord, error) {
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	// Spreadsheet apps often start the file with a byte order mark
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	var records []services.RiddleRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return row[i]
+			}
+			return ""
+		}
+		record := services.RiddleRecord{
+			Title:           field("title"),
+			Description:     field("description"),
+			Answer:          field("answer"),
+			AcceptedAnswers: splitCSVList(field("accepted_answers")),
+			Explanation:     field("explanation"),
+			Category:        field("category"),
+			Difficulty:      field("difficulty"),
+			Tags:            splitCSVList(field("tags")),
+			RiddleFormat:    services.RiddleFormat{Type: field("type")},
+		}
+		if err := unmarshalCSVJSON(field("payload"), &record.Payload); err != nil {
+			return nil, fmt.Errorf("record %d: payload: %w", len(records)+1, err)
+		}
+		if err := unmarshalCSVJSON(field("key"), &record.Key); err != nil {
+			return nil, fmt.Errorf("record %d: key: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
+
+func marshalCSVJSON(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func unmarshalCSVJSON(value string, dst interface{}) error {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(value), dst)
+}
+
+func splitCSVList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, csvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
\ No newline at end of file
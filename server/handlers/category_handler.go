@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"riddles-server/repository"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CategoryHandler struct {
+	categoryService services.CategoryService
+	riddleService   services.RiddleService
+}
+
+func NewCategoryHandler(categoryService services.CategoryService, riddleService services.RiddleService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		riddleService:   riddleService,
+	}
+}
+
+func (h *CategoryHandler) GetCategories(c echo.Context) error {
+	tree, err := h.categoryService.GetTree()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch categories")
+	}
+
+	return c.JSON(http.StatusOK, tree)
+}
+
+// GetCategory accepts either the category ID or its slug
+func (h *CategoryHandler) GetCategory(c echo.Context) error {
+	category, err := h.categoryService.GetCategory(c.Param("ref"))
+	if err != nil {
+		return categoryError(err)
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+// GetCategoryRiddles lists the riddles of the category and all of its
+// subcategories, with the same q and tags filters as the riddle list
+func (h *CategoryHandler) GetCategoryRiddles(c echo.Context) error {
+	categoryIDs, err := h.categoryService.GetSubtreeIDs(c.Param("ref"))
+	if err != nil {
+		return categoryError(err)
+	}
+
+	filter := repository.RiddleFilter{Query: c.QueryParam("q"), CategoryIDs: categoryIDs}
+	if tags := c.QueryParam("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	userID, _ := getUserID(c)
+
+	riddles, err := h.riddleService.GetRiddlesForUser(userID, filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch riddles")
+	}
+
+	riddlesWithProgress, err := h.riddleService.GetRiddlesWithUserProgress(riddles, userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch riddles with progress")
+	}
+
+	return c.JSON(http.StatusOK, riddlesWithProgress)
+}
+
+func (h *CategoryHandler) CreateCategory(c echo.Context) error {
+	var req services.CategoryInput
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	category, err := h.categoryService.CreateCategory(req)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	return c.JSON(http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+	}
+
+	var req services.CategoryInput
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	category, err := h.categoryService.UpdateCategory(uint(categoryID), req)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+func categoryError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrCategoryInvalid), errors.Is(err, services.ErrCategoryCycle):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrCategoryNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrCategoryNameTaken), errors.Is(err, services.ErrCategorySlugTaken):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process category")
+}
\ No newline at end of file
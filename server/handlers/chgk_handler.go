@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ChgkHandler struct {
+	chgkService services.ChgkService
+}
+
+func NewChgkHandler(chgkService services.ChgkService) *ChgkHandler {
+	return &ChgkHandler{
+		chgkService: chgkService,
+	}
+}
+
+type ChgkRegisterRequest struct {
+	TeamID uint `json:"team_id" validate:"required"`
+}
+
+func (h *ChgkHandler) CreateGame(c echo.Context) error {
+	var req services.ChgkGameOptions
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	game, err := h.chgkService.CreateGame(userID, req)
+	if err != nil {
+		return chgkError(err)
+	}
+
+	return c.JSON(http.StatusCreated, game)
+}
+
+func (h *ChgkHandler) GetGame(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	game, err := h.chgkService.GetGame(userID, uint(gameID))
+	if err != nil {
+		return chgkError(err)
+	}
+
+	return c.JSON(http.StatusOK, game)
+}
+
+func (h *ChgkHandler) RegisterTeam(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game ID")
+	}
+
+	var req ChgkRegisterRequest
+	if err := c.Bind(&req); err != nil || req.TeamID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	game, err := h.chgkService.RegisterTeam(userID, uint(gameID), req.TeamID)
+	if err != nil {
+		return chgkError(err)
+	}
+
+	return c.JSON(http.StatusOK, game)
+}
+
+func (h *ChgkHandler) NextQuestion(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	game, err := h.chgkService.NextQuestion(userID, uint(gameID))
+	if err != nil {
+		return chgkError(err)
+	}
+
+	return c.JSON(http.StatusOK, game)
+}
+
+func (h *ChgkHandler) SubmitAnswer(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game ID")
+	}
+
+	var req QuizAnswerRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	result, err := h.chgkService.SubmitAnswer(userID, uint(gameID), req.Answer)
+	if err != nil {
+		return chgkError(err)
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+func (h *ChgkHandler) GetResults(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game ID")
+	}
+
+	results, err := h.chgkService.GetResults(uint(gameID))
+	if err != nil {
+		return chgkError(err)
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+func chgkError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrChgkGameNotFound), errors.Is(err, services.ErrTeamNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrNotChgkHost), errors.Is(err, services.ErrNotTeamCaptain), errors.Is(err, services.ErrChgkNotCaptain):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrChgkRegistrationClosed), errors.Is(err, services.ErrChgkTeamRegistered),
+		errors.Is(err, services.ErrChgkPlayerRegistered), errors.Is(err, services.ErrChgkNoTeams),
+		errors.Is(err, services.ErrChgkRoundInProgress), errors.Is(err, services.ErrChgkGameFinished),
+		errors.Is(err, services.ErrChgkNoOpenQuestion), errors.Is(err, services.ErrChgkAlreadyAnswered):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrNotEnoughRiddles):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process game")
+}
\ No newline at end of file
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CommentHandler struct {
+	commentService services.CommentService
+}
+
+func NewCommentHandler(commentService services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+type CommentRequest struct {
+	Body     string `json:"body" validate:"required"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+type RemoveCommentRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+func (h *CommentHandler) GetComments(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	// Missing or malformed values fall back to the defaults
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	perPage, _ := strconv.Atoi(c.QueryParam("per_page"))
+
+	userID, _ := getUserID(c)
+
+	comments, err := h.commentService.GetComments(userID, uint(riddleID), page, perPage)
+	if err != nil {
+		return commentError(err)
+	}
+
+	return c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) AddComment(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req CommentRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	comment, err := h.commentService.AddComment(userID, uint(riddleID), req.ParentID, req.Body)
+	if err != nil {
+		return commentError(err)
+	}
+
+	return c.JSON(http.StatusCreated, comment)
+}
+
+func (h *CommentHandler) EditComment(c echo.Context) error {
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid comment ID")
+	}
+
+	var req CommentRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	comment, err := h.commentService.EditComment(userID, uint(commentID), req.Body)
+	if err != nil {
+		return commentError(err)
+	}
+
+	return c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) DeleteComment(c echo.Context) error {
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid comment ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	if err := h.commentService.DeleteComment(userID, uint(commentID)); err != nil {
+		return commentError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *CommentHandler) RemoveComment(c echo.Context) error {
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid comment ID")
+	}
+
+	var req RemoveCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	moderatorID, _ := getUserID(c)
+
+	if err := h.commentService.RemoveComment(moderatorID, uint(commentID), req.Reason); err != nil {
+		return commentError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func commentError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrCommentInvalid), errors.Is(err, services.ErrRemovalReasonRequired):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrCommentsLocked), errors.Is(err, services.ErrCommentNotAuthor):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrRiddleNotFound), errors.Is(err, services.ErrCommentNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrCommentGone), errors.Is(err, services.ErrCommentEditClosed),
+		errors.Is(err, services.ErrCommentDeleteClosed), errors.Is(err, services.ErrCommentTooDeep):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process comment")
+}
\ No newline at end of file
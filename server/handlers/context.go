@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"riddles-server/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getUserID returns the ID of the authenticated user set by the auth middleware
+func getUserID(c echo.Context) (uint, bool) {
+	userID, ok := c.Get(middleware.UserIDKey).(uint)
+	return userID, ok && userID != 0
+}
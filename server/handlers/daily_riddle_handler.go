@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 	"riddles-server/services"
+	"riddles-server/utils"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type DailyRiddleHandler struct {
@@ -20,6 +23,9 @@ func NewDailyRiddleHandler(dailyRiddleService services.DailyRiddleService) *Dail
 
 func (h *DailyRiddleHandler) GetTodayRiddle(c echo.Context) error {
 	dailyRiddle, err := h.dailyRiddleService.GetTodayRiddle()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "No riddle found for today")
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch today's riddle")
 	}
@@ -39,6 +45,9 @@ func (h *DailyRiddleHandler) GetRiddleByDate(c echo.Context) error {
 	}
 
 	dailyRiddle, err := h.dailyRiddleService.GetRiddleByDate(date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "No riddle found for date")
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch riddle for date")
 	}
@@ -48,4 +57,43 @@ func (h *DailyRiddleHandler) GetRiddleByDate(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, dailyRiddle)
+}
+
+type DailyAnswer struct {
+	RiddleID uint   `json:"riddle_id"`
+	Title    string `json:"title"`
+	Answer   string `json:"answer"`
+}
+
+func (h *DailyRiddleHandler) GetAnswersByDate(c echo.Context) error {
+	dateStr := c.Param("date")
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+	}
+
+	// Answers are published once the game day is over
+	if utils.DaysBetween(date, utils.GameToday()) < 1 {
+		return echo.NewHTTPError(http.StatusForbidden, "Answers are published the next day")
+	}
+
+	dailyRiddles, err := h.dailyRiddleService.GetRiddlesByDate(date)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch riddles for date")
+	}
+
+	if len(dailyRiddles) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "No riddles found for date")
+	}
+
+	answers := make([]DailyAnswer, len(dailyRiddles))
+	for i, dailyRiddle := range dailyRiddles {
+		answers[i] = DailyAnswer{
+			RiddleID: dailyRiddle.RiddleID,
+			Title:    dailyRiddle.Riddle.Title,
+			Answer:   services.SolutionOf(&dailyRiddle.Riddle),
+		}
+	}
+
+	return c.JSON(http.StatusOK, answers)
 }
\ No newline at end of file
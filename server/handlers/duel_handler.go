@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DuelHandler struct {
+	duelService services.DuelService
+}
+
+func NewDuelHandler(duelService services.DuelService) *DuelHandler {
+	return &DuelHandler{
+		duelService: duelService,
+	}
+}
+
+func (h *DuelHandler) CreateDuel(c echo.Context) error {
+	var req services.DuelOptions
+	if err := c.Bind(&req); err != nil || req.OpponentID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	duel, err := h.duelService.CreateDuel(userID, req)
+	if err != nil {
+		return duelError(err)
+	}
+
+	return c.JSON(http.StatusCreated, duel)
+}
+
+func (h *DuelHandler) ListDuels(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	duels, err := h.duelService.ListDuels(userID, c.QueryParam("status"))
+	if err != nil {
+		return duelError(err)
+	}
+
+	return c.JSON(http.StatusOK, duels)
+}
+
+func (h *DuelHandler) GetDuel(c echo.Context) error {
+	return h.withDuel(c, http.StatusOK, func(userID, duelID uint) (interface{}, error) {
+		return h.duelService.GetDuel(userID, duelID)
+	})
+}
+
+func (h *DuelHandler) AcceptDuel(c echo.Context) error {
+	return h.withDuel(c, http.StatusOK, func(userID, duelID uint) (interface{}, error) {
+		return h.duelService.AcceptDuel(userID, duelID)
+	})
+}
+
+func (h *DuelHandler) DeclineDuel(c echo.Context) error {
+	return h.withDuel(c, http.StatusOK, func(userID, duelID uint) (interface{}, error) {
+		return h.duelService.DeclineDuel(userID, duelID)
+	})
+}
+
+func (h *DuelHandler) StartDuel(c echo.Context) error {
+	return h.withDuel(c, http.StatusOK, func(userID, duelID uint) (interface{}, error) {
+		return h.duelService.StartDuel(userID, duelID)
+	})
+}
+
+func (h *DuelHandler) SubmitAnswer(c echo.Context) error {
+	var req QuizAnswerRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	return h.withDuel(c, http.StatusOK, func(userID, duelID uint) (interface{}, error) {
+		return h.duelService.SubmitAnswer(userID, duelID, req.Answer)
+	})
+}
+
+func (h *DuelHandler) SkipRiddle(c echo.Context) error {
+	return h.withDuel(c, http.StatusOK, func(userID, duelID uint) (interface{}, error) {
+		return h.duelService.SkipRiddle(userID, duelID)
+	})
+}
+
+func (h *DuelHandler) withDuel(c echo.Context, status int, action func(userID, duelID uint) (interface{}, error)) error {
+	duelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid duel ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	result, err := action(userID, uint(duelID))
+	if err != nil {
+		return duelError(err)
+	}
+
+	return c.JSON(status, result)
+}
+
+func duelError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrDuelNotFound), errors.Is(err, services.ErrDuelOpponentInvalid):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrDuelNotOpponent):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrDuelSelfChallenge):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrDuelNotPending), errors.Is(err, services.ErrDuelOver),
+		errors.Is(err, services.ErrDuelNotAccepted), errors.Is(err, services.ErrDuelAlreadyPlayed),
+		errors.Is(err, services.ErrDuelNotStarted), errors.Is(err, services.ErrDuelAlreadyAnswered):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrNotEnoughRiddles):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process duel")
+}
\ No newline at end of file
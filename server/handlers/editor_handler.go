@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EditorHandler struct {
+	editorService services.EditorService
+}
+
+func NewEditorHandler(editorService services.EditorService) *EditorHandler {
+	return &EditorHandler{
+		editorService: editorService,
+	}
+}
+
+type RollbackRequest struct {
+	Version int `json:"version" validate:"required"` // current version of the riddle
+}
+
+func (h *EditorHandler) CreateRiddle(c echo.Context) error {
+	var req services.RiddleEdit
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	editorID, _ := getUserID(c)
+
+	riddle, err := h.editorService.CreateRiddle(editorID, req)
+	if err != nil {
+		return editorError(err)
+	}
+
+	return c.JSON(http.StatusCreated, riddle)
+}
+
+// UpdateRiddle replaces the riddle's content; the body carries the version it was based on
+func (h *EditorHandler) UpdateRiddle(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req services.RiddleEdit
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	editorID, _ := getUserID(c)
+
+	riddle, err := h.editorService.UpdateRiddle(editorID, uint(riddleID), req)
+	if err != nil {
+		return editorError(err)
+	}
+
+	return c.JSON(http.StatusOK, riddle)
+}
+
+func (h *EditorHandler) GetHistory(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	history, err := h.editorService.GetHistory(uint(riddleID))
+	if err != nil {
+		return editorError(err)
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// Rollback restores the content of the revision in the URL
+func (h *EditorHandler) Rollback(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid revision version")
+	}
+
+	var req RollbackRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	editorID, _ := getUserID(c)
+
+	riddle, err := h.editorService.Rollback(editorID, uint(riddleID), version, req.Version)
+	if err != nil {
+		return editorError(err)
+	}
+
+	return c.JSON(http.StatusOK, riddle)
+}
+
+// ListRiddles shows riddles in every status, optionally narrowed by ?status=
+func (h *EditorHandler) ListRiddles(c echo.Context) error {
+	riddles, err := h.editorService.ListRiddles(c.QueryParam("status"))
+	if err != nil {
+		return editorError(err)
+	}
+
+	return c.JSON(http.StatusOK, riddles)
+}
+
+func (h *EditorHandler) SetStatus(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req services.StatusChange
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	editorID, _ := getUserID(c)
+
+	riddle, err := h.editorService.SetStatus(editorID, uint(riddleID), req)
+	if err != nil {
+		return editorError(err)
+	}
+
+	return c.JSON(http.StatusOK, riddle)
+}
+
+func (h *EditorHandler) DeleteRiddle(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	if err := h.editorService.DeleteRiddle(uint(riddleID)); err != nil {
+		return editorError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func editorError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrRiddleInvalid), errors.Is(err, services.ErrRiddleStatusInvalid),
+		errors.Is(err, services.ErrPublishAtRequired):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRiddleNotFound), errors.Is(err, services.ErrRevisionNotFound),
+		errors.Is(err, services.ErrCategoryNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrRiddleVersionConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process riddle")
+}
\ No newline at end of file
@@ -24,9 +24,7 @@ func (h *FavoriteHandler) AddFavorite(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
 	}
 
-	// TODO: Extract user ID from JWT token
-	// For now, we'll use a placeholder
-	userID := uint(1)
+	userID, _ := getUserID(c)
 
 	err = h.favoriteService.AddFavorite(userID, uint(riddleID))
 	if err != nil {
@@ -42,9 +40,7 @@ func (h *FavoriteHandler) RemoveFavorite(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
 	}
 
-	// TODO: Extract user ID from JWT token
-	// For now, we'll use a placeholder
-	userID := uint(1)
+	userID, _ := getUserID(c)
 
 	err = h.favoriteService.RemoveFavorite(userID, uint(riddleID))
 	if err != nil {
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"riddles-server/models"
+	"riddles-server/services"
+	"riddles-server/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// feedDays is how many past game days the feeds publish
+const feedDays = 30
+
+type FeedHandler struct {
+	dailyRiddleService services.DailyRiddleService
+	baseURL            string
+}
+
+func NewFeedHandler(dailyRiddleService services.DailyRiddleService, baseURL string) *FeedHandler {
+	return &FeedHandler{
+		dailyRiddleService: dailyRiddleService,
+		baseURL:            strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// feedDay holds the daily set of a single game day
+type feedDay struct {
+	Date     time.Time
+	Riddles  []models.Riddle
+	Modified time.Time
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func (h *FeedHandler) GetAtomFeed(c echo.Context) error {
+	days, modified, err := h.loadDays()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch daily riddles")
+	}
+
+	feed := atomFeed{
+		Title:   "Загадки дня",
+		ID:      h.baseURL + "/feeds/daily.atom",
+		Updated: modified.Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Href: h.baseURL + "/feeds/daily.atom"},
+		},
+	}
+	for _, day := range days {
+		date := day.Date.Format("2006-01-02")
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   "Загадки дня " + date,
+			ID:      h.baseURL + "/feeds/daily/" + date,
+			Updated: day.Modified.Format(time.RFC3339),
+			Links: []atomLink{
+				{Rel: "related", Href: h.answersURL(day.Date.AddDate(0, 0, -1))},
+			},
+			Content: atomContent{Type: "html", Body: h.dayHTML(day)},
+		})
+	}
+
+	return h.renderXML(c, "application/atom+xml", feed, modified)
+}
+
+func (h *FeedHandler) GetRSSFeed(c echo.Context) error {
+	days, modified, err := h.loadDays()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch daily riddles")
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         "Загадки дня",
+			Link:          h.baseURL,
+			Description:   "Ежедневная подборка загадок",
+			LastBuildDate: modified.Format(time.RFC1123Z),
+		},
+	}
+	for _, day := range days {
+		date := day.Date.Format("2006-01-02")
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       "Загадки дня " + date,
+			Link:        h.answersURL(day.Date.AddDate(0, 0, -1)),
+			GUID:        rssGUID{Value: h.baseURL + "/feeds/daily/" + date},
+			PubDate:     day.Modified.Format(time.RFC1123Z),
+			Description: h.dayHTML(day),
+		})
+	}
+
+	return h.renderXML(c, "application/rss+xml", feed, modified)
+}
+
+func (h *FeedHandler) GetICalendar(c echo.Context) error {
+	days, modified, err := h.loadDays()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch daily riddles")
+	}
+
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//riddles//daily riddles//RU")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "X-WR-CALNAME:Загадки дня")
+	for _, day := range days {
+		date := day.Date.Format("2006-01-02")
+
+		var description strings.Builder
+		for _, riddle := range day.Riddles {
+			fmt.Fprintf(&description, "%s: %s\n", riddle.Title, riddle.Description)
+		}
+		fmt.Fprintf(&description, "Ответы на вчерашние загадки: %s", h.answersURL(day.Date.AddDate(0, 0, -1)))
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:daily-"+date+"@riddles")
+		writeICSLine(&b, "DTSTAMP:"+day.Modified.UTC().Format("20060102T150405Z"))
+		writeICSLine(&b, "DTSTART;VALUE=DATE:"+day.Date.Format("20060102"))
+		writeICSLine(&b, "DTEND;VALUE=DATE:"+day.Date.AddDate(0, 0, 1).Format("20060102"))
+		writeICSLine(&b, "SUMMARY:"+escapeICS("Загадки дня "+date))
+		writeICSLine(&b, "DESCRIPTION:"+escapeICS(description.String()))
+		writeICSLine(&b, "URL:"+h.answersURL(day.Date.AddDate(0, 0, -1)))
+		writeICSLine(&b, "END:VEVENT")
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+
+	return h.render(c, "text/calendar; charset=utf-8", []byte(b.String()), modified)
+}
+
+// loadDays groups the daily riddles of the last feedDays game days, newest first
+func (h *FeedHandler) loadDays() ([]feedDay, time.Time, error) {
+	today := utils.GameToday()
+	dailyRiddles, err := h.dailyRiddleService.GetRiddlesForDateRange(today.AddDate(0, 0, -feedDays+1), today)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var days []feedDay
+	var modified time.Time
+	for _, dailyRiddle := range dailyRiddles {
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(dailyRiddle.FeaturedDate) {
+			days = append(days, feedDay{Date: dailyRiddle.FeaturedDate})
+		}
+		day := &days[len(days)-1]
+		day.Riddles = append(day.Riddles, dailyRiddle.Riddle)
+		if dailyRiddle.CreatedAt.After(day.Modified) {
+			day.Modified = dailyRiddle.CreatedAt
+		}
+		if dailyRiddle.CreatedAt.After(modified) {
+			modified = dailyRiddle.CreatedAt
+		}
+	}
+
+	for i, j := 0, len(days)-1; i < j; i, j = i+1, j-1 {
+		days[i], days[j] = days[j], days[i]
+	}
+
+	return days, modified.UTC().Truncate(time.Second), nil
+}
+
+// dayHTML renders the riddles of a day without their answers
+func (h *FeedHandler) dayHTML(day feedDay) string {
+	var b strings.Builder
+	for _, riddle := range day.Riddles {
+		fmt.Fprintf(&b, "<h3>%s</h3><p>%s</p><p><small>%s, %s</small></p>",
+			html.EscapeString(riddle.Title),
+			html.EscapeString(riddle.Description),
+			html.EscapeString(riddle.Category.Name),
+			html.EscapeString(riddle.DisplayedDifficulty()))
+	}
+	fmt.Fprintf(&b, `<p><a href="%s">Ответы на вчерашние загадки</a></p>`, html.EscapeString(h.answersURL(day.Date.AddDate(0, 0, -1))))
+	return b.String()
+}
+
+func (h *FeedHandler) answersURL(date time.Time) string {
+	return h.baseURL + "/api/daily-riddle/" + date.Format("2006-01-02") + "/answers"
+}
+
+func (h *FeedHandler) renderXML(c echo.Context, contentType string, feed interface{}, modified time.Time) error {
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render feed")
+	}
+
+	return h.render(c, contentType+"; charset=utf-8", append([]byte(xml.Header), body...), modified)
+}
+
+// render writes the feed body, answering conditional requests with 304 Not Modified
+func (h *FeedHandler) render(c echo.Context, contentType string, body []byte, modified time.Time) error {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "public, max-age=300")
+	if !modified.IsZero() {
+		header.Set(echo.HeaderLastModified, modified.Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request(), etag, modified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, contentType, body)
+}
+
+func notModified(req *http.Request, etag string, modified time.Time) bool {
+	// If-None-Match takes precedence over If-Modified-Since (RFC 9110, 13.2.2)
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := req.Header.Get(echo.HeaderIfModifiedSince); ims != "" && !modified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !modified.After(since)
+	}
+
+	return false
+}
+
+// writeICSLine writes a content line folded at 75 octets as required by RFC 5545
+func writeICSLine(b *strings.Builder, line string) {
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
+
+func escapeICS(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"riddles-server/services"
+	"riddles-server/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+type LeaderboardHandler struct {
+	leaderboardService services.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService services.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+func (h *LeaderboardHandler) GetAllTime(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	leaderboard, err := h.leaderboardService.GetAllTime(leaderboardLimit(c), userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch leaderboard")
+	}
+
+	return c.JSON(http.StatusOK, leaderboard)
+}
+
+func (h *LeaderboardHandler) GetWeekly(c echo.Context) error {
+	return h.getRolling(c, services.BoardWeekly)
+}
+
+func (h *LeaderboardHandler) GetMonthly(c echo.Context) error {
+	return h.getRolling(c, services.BoardMonthly)
+}
+
+func (h *LeaderboardHandler) getRolling(c echo.Context, board string) error {
+	userID, _ := getUserID(c)
+
+	leaderboard, err := h.leaderboardService.GetRolling(board, leaderboardLimit(c), userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch leaderboard")
+	}
+
+	return c.JSON(http.StatusOK, leaderboard)
+}
+
+func (h *LeaderboardHandler) GetCategory(c echo.Context) error {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	leaderboard, err := h.leaderboardService.GetCategory(uint(categoryID), leaderboardLimit(c), userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch leaderboard")
+	}
+
+	return c.JSON(http.StatusOK, leaderboard)
+}
+
+func (h *LeaderboardHandler) GetDailySpeed(c echo.Context) error {
+	date := utils.GameToday()
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+		}
+		date = parsed
+	}
+
+	userID, _ := getUserID(c)
+
+	leaderboard, err := h.leaderboardService.GetDailySpeed(date, leaderboardLimit(c), userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch leaderboard")
+	}
+
+	return c.JSON(http.StatusOK, leaderboard)
+}
+
+func leaderboardLimit(c echo.Context) int {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		return defaultLeaderboardLimit
+	}
+	if limit > maxLeaderboardLimit {
+		return maxLeaderboardLimit
+	}
+	return limit
+}
\ No newline at end of file
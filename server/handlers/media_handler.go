@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"riddles-server/services"
+	"riddles-server/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MediaHandler struct {
+	mediaService services.MediaService
+}
+
+func NewMediaHandler(mediaService services.MediaService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// Upload attaches the image in the multipart field "file" to the riddle
+func (h *MediaHandler) Upload(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "A file is required")
+	}
+	if header.Size > services.MaxMediaSize {
+		return mediaError(services.ErrMediaTooLarge)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid file")
+	}
+	defer file.Close()
+
+	editorID, _ := getUserID(c)
+
+	media, err := h.mediaService.Upload(editorID, uint(riddleID), header.Header.Get(echo.HeaderContentType), file)
+	if err != nil {
+		return mediaError(err)
+	}
+
+	return c.JSON(http.StatusCreated, media)
+}
+
+func (h *MediaHandler) GetMedia(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	media, err := h.mediaService.GetMedia(uint(riddleID))
+	if err != nil {
+		return mediaError(err)
+	}
+
+	return c.JSON(http.StatusOK, media)
+}
+
+func (h *MediaHandler) Delete(c echo.Context) error {
+	mediaID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid media ID")
+	}
+
+	if err := h.mediaService.Delete(uint(mediaID)); err != nil {
+		return mediaError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func mediaError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrMediaTooLarge):
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, services.ErrMediaType), errors.Is(err, services.ErrMediaTypeMismatch):
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, services.ErrMediaUnreadable), errors.Is(err, services.ErrMediaDimensions),
+		errors.Is(err, services.ErrTooManyMedia):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRiddleNotFound), errors.Is(err, services.ErrMediaNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process media")
+}
+
+// LocalMediaHandler serves files of the local blob store to holders of a
+// signed URL
+type LocalMediaHandler struct {
+	store *storage.LocalStore
+}
+
+func NewLocalMediaHandler(store *storage.LocalStore) *LocalMediaHandler {
+	return &LocalMediaHandler{
+		store: store,
+	}
+}
+
+func (h *LocalMediaHandler) Serve(c echo.Context) error {
+	key := c.Param("*")
+	if !h.store.Verify(key, c.QueryParam("expires"), c.QueryParam("signature")) {
+		return echo.NewHTTPError(http.StatusForbidden, "Link is invalid or has expired")
+	}
+
+	// Invalid keys can't carry a valid signature, so any error here is a missing file
+	file, err := h.store.Open(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "File not found")
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to read file")
+	}
+
+	// Blobs never change, so browsers may keep them as long as the link lasts
+	c.Response().Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(services.MediaURLLifetime.Seconds())))
+	http.ServeContent(c.Response(), c.Request(), path.Base(key), info.ModTime(), file)
+	return nil
+}
\ No newline at end of file
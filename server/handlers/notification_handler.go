@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) GetNotifications(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	notifications, err := h.notificationService.GetNotifications(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get notifications")
+	}
+
+	return c.JSON(http.StatusOK, notifications)
+}
+
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	notificationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid notification ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	if err := h.notificationService.MarkRead(userID, uint(notificationID)); err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update notification")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	if err := h.notificationService.MarkAllRead(userID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update notifications")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PlacementHandler struct {
+	placementService services.PlacementService
+}
+
+func NewPlacementHandler(placementService services.PlacementService) *PlacementHandler {
+	return &PlacementHandler{
+		placementService: placementService,
+	}
+}
+
+func (h *PlacementHandler) StartTest(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	test, err := h.placementService.StartTest(userID)
+	if err != nil {
+		return placementError(err)
+	}
+
+	return c.JSON(http.StatusOK, test)
+}
+
+func (h *PlacementHandler) GetTest(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	test, err := h.placementService.GetTest(userID)
+	if err != nil {
+		return placementError(err)
+	}
+
+	return c.JSON(http.StatusOK, test)
+}
+
+func (h *PlacementHandler) SubmitAnswer(c echo.Context) error {
+	// An empty answer counts as "don't know"
+	var req QuizAnswerRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	test, err := h.placementService.SubmitAnswer(userID, req.Answer)
+	if err != nil {
+		return placementError(err)
+	}
+
+	return c.JSON(http.StatusOK, test)
+}
+
+func placementError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrPlacementNotStarted):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrPlacementAnswered):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrNotEnoughRiddles):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process placement test")
+}
\ No newline at end of file
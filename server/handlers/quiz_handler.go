@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type QuizHandler struct {
+	quizService services.QuizService
+}
+
+func NewQuizHandler(quizService services.QuizService) *QuizHandler {
+	return &QuizHandler{
+		quizService: quizService,
+	}
+}
+
+type QuizAnswerRequest struct {
+	Answer string `json:"answer" validate:"required"`
+}
+
+func (h *QuizHandler) CreateQuiz(c echo.Context) error {
+	var req services.QuizOptions
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	quiz, err := h.quizService.CreateQuiz(userID, req)
+	if err != nil {
+		return quizError(err)
+	}
+
+	return c.JSON(http.StatusCreated, quiz)
+}
+
+func (h *QuizHandler) GetQuiz(c echo.Context) error {
+	quizID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid quiz ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	quiz, err := h.quizService.GetQuiz(userID, uint(quizID))
+	if err != nil {
+		return quizError(err)
+	}
+
+	return c.JSON(http.StatusOK, quiz)
+}
+
+func (h *QuizHandler) SubmitAnswer(c echo.Context) error {
+	quizID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid quiz ID")
+	}
+
+	position, err := strconv.Atoi(c.Param("position"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid question number")
+	}
+
+	var req QuizAnswerRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	result, err := h.quizService.SubmitAnswer(userID, uint(quizID), position, req.Answer)
+	if err != nil {
+		return quizError(err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *QuizHandler) FinishQuiz(c echo.Context) error {
+	quizID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid quiz ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	quiz, err := h.quizService.FinishQuiz(userID, uint(quizID))
+	if err != nil {
+		return quizError(err)
+	}
+
+	return c.JSON(http.StatusOK, quiz)
+}
+
+func quizError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrQuizNotFound), errors.Is(err, services.ErrQuizQuestionInvalid):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrQuizOver), errors.Is(err, services.ErrQuizAlreadyAnswered):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrNotEnoughRiddles):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process quiz")
+}
\ No newline at end of file
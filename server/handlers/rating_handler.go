@@ -33,9 +33,7 @@ func (h *RatingHandler) RateRiddle(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
 
-	// TODO: Extract user ID from JWT token
-	// For now, we'll use a placeholder
-	userID := uint(1)
+	userID, _ := getUserID(c)
 
 	err = h.ratingService.RateRiddle(userID, uint(riddleID), req.Rating)
 	if err != nil {
@@ -51,9 +49,7 @@ func (h *RatingHandler) RemoveRating(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
 	}
 
-	// TODO: Extract user ID from JWT token
-	// For now, we'll use a placeholder
-	userID := uint(1)
+	userID, _ := getUserID(c)
 
 	err = h.ratingService.RemoveRating(userID, uint(riddleID))
 	if err != nil {
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RecommendationHandler struct {
+	recommendationService services.RecommendationService
+}
+
+func NewRecommendationHandler(recommendationService services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+	}
+}
+
+func (h *RecommendationHandler) GetRecommended(c echo.Context) error {
+	// Anonymous users get the popularity list
+	userID, _ := getUserID(c)
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	recommendations, err := h.recommendationService.GetRecommendations(userID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get recommendations")
+	}
+
+	return c.JSON(http.StatusOK, recommendations)
+}
\ No newline at end of file
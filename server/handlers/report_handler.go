@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReportHandler struct {
+	reportService services.ReportService
+}
+
+func NewReportHandler(reportService services.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+type ReportRequest struct {
+	Reason  string `json:"reason" validate:"required"`
+	Comment string `json:"comment"`
+}
+
+func (h *ReportHandler) Report(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req ReportRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	report, err := h.reportService.Report(userID, uint(riddleID), req.Reason, req.Comment)
+	if err != nil {
+		return reportError(err)
+	}
+
+	return c.JSON(http.StatusCreated, report)
+}
+
+func (h *ReportHandler) GetMyReports(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	reports, err := h.reportService.GetUserReports(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get reports")
+	}
+
+	return c.JSON(http.StatusOK, reports)
+}
+
+func (h *ReportHandler) GetQueue(c echo.Context) error {
+	groups, err := h.reportService.GetQueue()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get report queue")
+	}
+
+	return c.JSON(http.StatusOK, groups)
+}
+
+func (h *ReportHandler) Resolve(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req services.ResolutionInput
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	moderatorID, _ := getUserID(c)
+
+	resolution, err := h.reportService.Resolve(moderatorID, uint(riddleID), req)
+	if err != nil {
+		return reportError(err)
+	}
+
+	return c.JSON(http.StatusOK, resolution)
+}
+
+func reportError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrReportReasonInvalid), errors.Is(err, services.ErrReportCommentTooLong),
+		errors.Is(err, services.ErrReportOutcomeInvalid), errors.Is(err, services.ErrReportFixLinkRequired),
+		errors.Is(err, services.ErrReportNoteRequired):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRiddleNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrReportDuplicate), errors.Is(err, services.ErrNoOpenReports):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process report")
+}
\ No newline at end of file
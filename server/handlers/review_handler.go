@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReviewHandler struct {
+	reviewService services.ReviewService
+}
+
+func NewReviewHandler(reviewService services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+type ReviewRequest struct {
+	Answer string `json:"answer" validate:"required"`
+	Grade  int    `json:"grade"` // 3 (hard) to 5 (easy), used when the answer is correct
+}
+
+func (h *ReviewHandler) GetDue(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	queue, err := h.reviewService.GetDue(userID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get review queue")
+	}
+
+	return c.JSON(http.StatusOK, queue)
+}
+
+func (h *ReviewHandler) SubmitReview(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("riddle_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req ReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	result, err := h.reviewService.SubmitReview(userID, uint(riddleID), req.Answer, req.Grade)
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrReviewNotDue):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrReviewGradeInvalid):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to submit review")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
\ No newline at end of file
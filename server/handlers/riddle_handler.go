@@ -1,20 +1,27 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"riddles-server/models"
+	"riddles-server/repository"
 	"riddles-server/services"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type RiddleHandler struct {
 	riddleService services.RiddleService
+	eloService    services.EloService
 }
 
-func NewRiddleHandler(riddleService services.RiddleService) *RiddleHandler {
+func NewRiddleHandler(riddleService services.RiddleService, eloService services.EloService) *RiddleHandler {
 	return &RiddleHandler{
 		riddleService: riddleService,
+		eloService:    eloService,
 	}
 }
 
@@ -27,16 +34,22 @@ type CheckAnswerResponse struct {
 	Message string `json:"message"`
 }
 
+// GetAllRiddles lists riddles, optionally searched with q and narrowed to
+// riddles carrying every tag in the comma-separated tags parameter
 func (h *RiddleHandler) GetAllRiddles(c echo.Context) error {
-	riddles, err := h.riddleService.GetAllRiddles()
+	filter := repository.RiddleFilter{Query: c.QueryParam("q")}
+	if tags := c.QueryParam("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	// Anonymous users get riddles without personalized data
+	userID, _ := getUserID(c)
+
+	riddles, err := h.riddleService.GetRiddlesForUser(userID, filter)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch riddles")
 	}
 
-	// TODO: Extract user ID from JWT token for personalized data
-	// For now, we'll use a placeholder
-	userID := uint(1)
-
 	riddlesWithProgress, err := h.riddleService.GetRiddlesWithUserProgress(riddles, userID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch riddles with progress")
@@ -45,20 +58,76 @@ func (h *RiddleHandler) GetAllRiddles(c echo.Context) error {
 	return c.JSON(http.StatusOK, riddlesWithProgress)
 }
 
+// GetRandomRiddle picks a riddle matching the optional category_id, difficulty
+// and unsolved filters. Anonymous players don't see repeats within a browser
+// session: the riddles they were shown are kept in a session cookie.
+func (h *RiddleHandler) GetRandomRiddle(c echo.Context) error {
+	var filter repository.RandomRiddleFilter
+	if categoryID := c.QueryParam("category_id"); categoryID != "" {
+		id, err := strconv.Atoi(categoryID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+		}
+		filter.CategoryID = uint(id)
+	}
+	filter.Difficulty = c.QueryParam("difficulty")
+
+	userID, authenticated := getUserID(c)
+	if authenticated {
+		if unsolved, _ := strconv.ParseBool(c.QueryParam("unsolved")); unsolved {
+			filter.ExcludeSolvedBy = userID
+		}
+	} else {
+		filter.ExcludeIDs = seenRiddles(c)
+	}
+
+	riddle, err := h.riddleService.GetRandomRiddle(filter)
+	if errors.Is(err, gorm.ErrRecordNotFound) && len(filter.ExcludeIDs) > 0 {
+		// Everything was shown already; start the session over
+		filter.ExcludeIDs = nil
+		riddle, err = h.riddleService.GetRandomRiddle(filter)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "No riddles match the filters")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to pick a riddle")
+	}
+
+	if authenticated {
+		if err := h.riddleService.StartRiddle(userID, riddle.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open riddle")
+		}
+	} else {
+		setSeenRiddles(c, append(filter.ExcludeIDs, riddle.ID))
+	}
+
+	riddlesWithProgress, err := h.riddleService.GetRiddlesWithUserProgress([]models.Riddle{*riddle}, userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch riddle with progress")
+	}
+
+	return c.JSON(http.StatusOK, riddlesWithProgress[0])
+}
+
 func (h *RiddleHandler) GetRiddleByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
 	}
 
-	// TODO: Extract user ID from JWT token for personalized data
-	// For now, we'll use a placeholder
-	userID := uint(1)
+	// Anonymous users get riddles without personalized data
+	userID, authenticated := getUserID(c)
 
 	riddleWithProgress, err := h.riddleService.GetRiddleWithUserProgress(uint(id), userID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "Riddle not found")
 	}
+	if authenticated {
+		if err := h.riddleService.StartRiddle(userID, uint(id)); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open riddle")
+		}
+	}
 
 	return c.JSON(http.StatusOK, riddleWithProgress)
 }
@@ -74,10 +143,18 @@ func (h *RiddleHandler) CheckAnswer(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
 
-	correct, err := h.riddleService.CheckAnswer(uint(id), req.Answer)
-	if err != nil {
+	var correct bool
+	if userID, ok := getUserID(c); ok {
+		correct, err = h.riddleService.SubmitAnswer(userID, uint(id), req.Answer)
+	} else {
+		correct, err = h.riddleService.CheckAnswer(uint(id), req.Answer)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return echo.NewHTTPError(http.StatusNotFound, "Riddle not found")
 	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save answer")
+	}
 
 	response := CheckAnswerResponse{
 		Correct: correct,
@@ -90,4 +167,101 @@ func (h *RiddleHandler) CheckAnswer(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, response)
+}
+
+func (h *RiddleHandler) GetHint(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	hint, err := h.riddleService.GetHint(userID, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Riddle not found")
+	}
+	if errors.Is(err, services.ErrHintsUnavailable) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get hint")
+	}
+
+	return c.JSON(http.StatusOK, hint)
+}
+
+func (h *RiddleHandler) RevealAnswer(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	reveal, err := h.riddleService.RevealAnswer(userID, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Riddle not found")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to reveal answer")
+	}
+
+	return c.JSON(http.StatusOK, reveal)
+}
+
+// GetNextRiddle suggests an unsolved riddle matching the player's rating
+func (h *RiddleHandler) GetNextRiddle(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	next, err := h.eloService.NextRiddle(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "No unsolved riddles left")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to pick next riddle")
+	}
+
+	return c.JSON(http.StatusOK, next)
+}
+
+const (
+	seenRiddlesCookie = "riddles_seen"
+	// maxSeenRiddles keeps the cookie small; the oldest riddles may come back after that
+	maxSeenRiddles = 200
+)
+
+func seenRiddles(c echo.Context) []uint {
+	cookie, err := c.Cookie(seenRiddlesCookie)
+	if err != nil {
+		return nil
+	}
+
+	var ids []uint
+	for _, part := range strings.Split(cookie.Value, ".") {
+		if id, err := strconv.ParseUint(part, 36, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+func setSeenRiddles(c echo.Context, ids []uint) {
+	if len(ids) > maxSeenRiddles {
+		ids = ids[len(ids)-maxSeenRiddles:]
+	}
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 36)
+	}
+
+	// No expiry: the cookie lives as long as the browser session
+	c.SetCookie(&http.Cookie{
+		Name:     seenRiddlesCookie,
+		Value:    strings.Join(parts, "."),
+		Path:     "/api/riddles",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
 }
\ No newline at end of file
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"riddles-server/services"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	roomWriteWait    = 10 * time.Second
+	roomPongWait     = 60 * time.Second
+	roomPingInterval = 30 * time.Second
+	roomMaxMessage   = 4096
+	roomMaxNameRunes = 30
+)
+
+type RoomHandler struct {
+	roomService services.RoomService
+	upgrader    websocket.Upgrader
+}
+
+func NewRoomHandler(roomService services.RoomService) *RoomHandler {
+	return &RoomHandler{
+		roomService: roomService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Players are identified by room keys rather than cookies, so
+			// cross-origin connections can't act on anyone's behalf
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func (h *RoomHandler) CreateRoom(c echo.Context) error {
+	var req services.RoomOptions
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	room, err := h.roomService.CreateRoom(req)
+	if errors.Is(err, services.ErrNotEnoughRiddles) {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create room")
+	}
+
+	return c.JSON(http.StatusCreated, room)
+}
+
+// Connect upgrades to a WebSocket. New players pass ?name=, the host and
+// reconnecting players pass the ?key= they were given.
+func (h *RoomHandler) Connect(c echo.Context) error {
+	room, err := h.roomService.GetRoom(c.Param("code"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Room not found")
+	}
+
+	key := c.QueryParam("key")
+	name := []rune(strings.TrimSpace(c.QueryParam("name")))
+	if key == "" && len(name) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Name is required")
+	}
+	if len(name) > roomMaxNameRunes {
+		name = name[:roomMaxNameRunes]
+	}
+
+	conn, err := room.Join(key, string(name))
+	switch {
+	case errors.Is(err, services.ErrRoomKey):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrRoomStarted), errors.Is(err, services.ErrRoomClosed):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to join room")
+	}
+
+	ws, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		conn.Close()
+		return nil // the upgrader already wrote the error response
+	}
+
+	go writeRoomMessages(ws, conn)
+	readRoomMessages(ws, conn)
+	return nil
+}
+
+func readRoomMessages(ws *websocket.Conn, conn *services.RoomConn) {
+	defer conn.Close()
+
+	ws.SetReadLimit(roomMaxMessage)
+	ws.SetReadDeadline(time.Now().Add(roomPongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(roomPongWait))
+	})
+
+	for {
+		var msg services.RoomClientMessage
+		if err := ws.ReadJSON(&msg); err != nil {
+			return
+		}
+		conn.Handle(msg)
+	}
+}
+
+// writeRoomMessages owns all writes to the socket and closes it once the room
+// closes the connection's outbox
+func writeRoomMessages(ws *websocket.Conn, conn *services.RoomConn) {
+	ticker := time.NewTicker(roomPingInterval)
+	defer func() {
+		ticker.Stop()
+		ws.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-conn.Messages():
+			ws.SetWriteDeadline(time.Now().Add(roomWriteWait))
+			if !ok {
+				ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := ws.WriteJSON(msg); err != nil {
+				conn.Close()
+				return
+			}
+		case <-ticker.C:
+			ws.SetWriteDeadline(time.Now().Add(roomWriteWait))
+			if err := ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+	"riddles-server/services"
+	"riddles-server/storage"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+// roomRiddleRepo serves a fixed set of riddles to the room service; the other
+// repository methods aren't used by rooms
+type roomRiddleRepo struct {
+	repository.RiddleRepository
+	riddles []models.Riddle
+}
+
+func (r *roomRiddleRepo) FindRandom(categoryID uint, difficulty string, limit int) ([]models.Riddle, error) {
+	return r.riddles, nil
+}
+
+type roomClient struct {
+	t  *testing.T
+	ws *websocket.Conn
+}
+
+func dialRoom(t *testing.T, server *httptest.Server, code string, query url.Values) *roomClient {
+	t.Helper()
+	address := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/rooms/" + code + "?" + query.Encode()
+	ws, _, err := websocket.DefaultDialer.Dial(address, nil)
+	if err != nil {
+		t.Fatalf("dial %s: %v", address, err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return &roomClient{t: t, ws: ws}
+}
+
+// expect reads messages until one of the given type arrives, skipping the
+// scoreboard updates and state messages sent in between
+func (c *roomClient) expect(msgType string) services.RoomMessage {
+	c.t.Helper()
+	c.ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var msg services.RoomMessage
+		if err := c.ws.ReadJSON(&msg); err != nil {
+			c.t.Fatalf("waiting for %s: %v", msgType, err)
+		}
+		if msg.Type == msgType {
+			return msg
+		}
+		if msg.Type == services.RoomMsgError {
+			c.t.Fatalf("waiting for %s: error %q", msgType, msg.Message)
+		}
+	}
+}
+
+func (c *roomClient) send(msgType, answer string) {
+	c.t.Helper()
+	if err := c.ws.WriteJSON(services.RoomClientMessage{Type: msgType, Answer: answer}); err != nil {
+		c.t.Fatalf("send %s: %v", msgType, err)
+	}
+}
+
+// newRoomMediaService signs URLs for a local store; rooms never touch the repositories
+func newRoomMediaService(t *testing.T) services.MediaService {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir(), "http://media.test/media", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return services.NewMediaService(nil, nil, store)
+}
+
+func TestRoomGame(t *testing.T) {
+	roomService := services.NewRoomService(&roomRiddleRepo{riddles: []models.Riddle{
+		{ID: 1, Title: "Эхо", Description: "Без языка, а отвечает", Answer: "эхо", Difficulty: "easy",
+			Media: []models.RiddleMedia{{ID: 7, Key: "riddles/1/echo.png"}}},
+		{ID: 2, Title: "Тень", Description: "Всюду ходит за тобой", Answer: "тень", Difficulty: "easy"},
+	}}, newRoomMediaService(t))
+	info, err := roomService.CreateRoom(services.RoomOptions{QuestionSeconds: 1, RevealSeconds: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	e.GET("/ws/rooms/:code", NewRoomHandler(roomService).Connect)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	// Join: the host connects with its key, players with a name
+	host := dialRoom(t, server, info.Code, url.Values{"key": {info.HostKey}})
+	if welcome := host.expect(services.RoomMsgWelcome); !welcome.IsHost || welcome.State != services.RoomLobby || welcome.Total != 2 {
+		t.Fatalf("host welcome = %+v", welcome)
+	}
+
+	anna := dialRoom(t, server, info.Code, url.Values{"name": {"Аня"}})
+	annaWelcome := anna.expect(services.RoomMsgWelcome)
+	if annaWelcome.IsHost || annaWelcome.Key == "" {
+		t.Fatalf("player welcome = %+v", annaWelcome)
+	}
+
+	boris := dialRoom(t, server, info.Code, url.Values{"name": {"Борис"}})
+	borisWelcome := boris.expect(services.RoomMsgWelcome)
+
+	scoreboard := host.expect(services.RoomMsgScoreboard)
+	for len(scoreboard.Scoreboard) < 2 {
+		scoreboard = host.expect(services.RoomMsgScoreboard)
+	}
+
+	// Question countdown: everyone gets the question with a deadline
+	host.send(services.RoomCmdStart, "")
+	for _, client := range []*roomClient{host, anna, boris} {
+		question := client.expect(services.RoomMsgQuestion)
+		if question.Index != 1 || question.Question == nil || question.Question.Title != "Эхо" || question.Deadline == nil {
+			t.Fatalf("question = %+v", question)
+		}
+		if question.Answer != "" {
+			t.Fatalf("question leaks the answer: %+v", question)
+		}
+		if media := question.Question.Media; len(media) != 1 || media[0].URL == "" {
+			t.Fatalf("question media = %+v", media)
+		}
+	}
+
+	// Answer collection
+	anna.send(services.RoomCmdAnswer, "Эхо")
+	result := anna.expect(services.RoomMsgAnswer)
+	if result.Correct == nil || !*result.Correct || result.Points <= 100 {
+		t.Fatalf("correct answer result = %+v", result)
+	}
+
+	// Reconnect by key: the player keeps its ID and gets the open question back
+	boris.ws.Close()
+	boris = dialRoom(t, server, info.Code, url.Values{"key": {borisWelcome.Key}})
+	if welcome := boris.expect(services.RoomMsgWelcome); welcome.PlayerID != borisWelcome.PlayerID {
+		t.Fatalf("reconnected as player %d, want %d", welcome.PlayerID, borisWelcome.PlayerID)
+	}
+	if question := boris.expect(services.RoomMsgQuestion); question.Index != 1 || question.Answered {
+		t.Fatalf("question after reconnect = %+v", question)
+	}
+
+	boris.send(services.RoomCmdAnswer, "ветер")
+	if result := boris.expect(services.RoomMsgAnswer); result.Correct == nil || *result.Correct {
+		t.Fatalf("wrong answer result = %+v", result)
+	}
+
+	// Scoreboard broadcast: everyone answered, so the reveal comes at once
+	for _, client := range []*roomClient{host, anna, boris} {
+		reveal := client.expect(services.RoomMsgReveal)
+		if reveal.Answer != "эхо" || len(reveal.Scoreboard) != 2 {
+			t.Fatalf("reveal = %+v", reveal)
+		}
+		if top := reveal.Scoreboard[0]; top.PlayerID != annaWelcome.PlayerID || top.Correct != 1 {
+			t.Fatalf("scoreboard = %+v", reveal.Scoreboard)
+		}
+	}
+
+	// The host skips the pause; nobody answers, so the countdown closes the question
+	host.send(services.RoomCmdNext, "")
+	if question := anna.expect(services.RoomMsgQuestion); question.Index != 2 {
+		t.Fatalf("second question = %+v", question)
+	}
+	if reveal := anna.expect(services.RoomMsgReveal); reveal.Index != 2 || reveal.Answer != "тень" {
+		t.Fatalf("second reveal = %+v", reveal)
+	}
+
+	finished := anna.expect(services.RoomMsgFinished)
+	if len(finished.Scoreboard) != 2 || finished.Scoreboard[0].Score != result.Points {
+		t.Fatalf("final scoreboard = %+v", finished.Scoreboard)
+	}
+	if room, err := roomService.GetRoom(info.Code); err != nil || room.State() != services.RoomFinished {
+		t.Fatalf("room state = %v, %v", room, err)
+	}
+}
+
+func TestCreateRoomClampsDurations(t *testing.T) {
+	roomService := services.NewRoomService(&roomRiddleRepo{riddles: []models.Riddle{{ID: 1, Answer: "эхо"}}}, newRoomMediaService(t))
+	info, err := roomService.CreateRoom(services.RoomOptions{QuestionSeconds: 1 << 30, RevealSeconds: 1 << 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Options.QuestionSeconds != 120 || info.Options.RevealSeconds != 30 {
+		t.Fatalf("options = %+v", info.Options)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+	"riddles-server/services"
+	"riddles-server/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ShareHandler struct {
+	shareService services.ShareService
+}
+
+func NewShareHandler(shareService services.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Загадки дня {{.Date}}</title>
+<meta property="og:title" content="Загадки дня {{.Date}}: {{.Solved}}/{{.Total}}">
+<meta property="og:description" content="{{.Text}}">
+<meta property="og:url" content="{{.URL}}">
+</head>
+<body>
+<h1>Загадки дня {{.Date}}</h1>
+<p style="font-size: 2em">{{.Grid}}</p>
+<p>{{.Solved}}/{{.Total}}, подсказок: {{.HintsUsed}}</p>
+</body>
+</html>
+`))
+
+func (h *ShareHandler) GetDailyShare(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	date := utils.GameToday()
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+		}
+		date = parsed
+	}
+
+	share, err := h.shareService.BuildDailyShare(userID, date)
+	if err != nil {
+		if errors.Is(err, services.ErrNoDailyRiddles) {
+			return echo.NewHTTPError(http.StatusNotFound, "No daily riddles found for date")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build share")
+	}
+
+	return c.JSON(http.StatusOK, share)
+}
+
+func (h *ShareHandler) ShowSharePage(c echo.Context) error {
+	share, err := h.shareService.ParseShareToken(c.Param("token"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Share not found")
+	}
+
+	if strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMEApplicationJSON) {
+		return c.JSON(http.StatusOK, share)
+	}
+
+	var page strings.Builder
+	if err := sharePageTemplate.Execute(&page, share); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render share page")
+	}
+
+	return c.HTML(http.StatusOK, page.String())
+}
\ No newline at end of file
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SubmissionHandler struct {
+	submissionService services.SubmissionService
+}
+
+func NewSubmissionHandler(submissionService services.SubmissionService) *SubmissionHandler {
+	return &SubmissionHandler{
+		submissionService: submissionService,
+	}
+}
+
+type RejectSubmissionRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+func (h *SubmissionHandler) Submit(c echo.Context) error {
+	var req services.RiddleInput
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	submission, err := h.submissionService.Submit(userID, req)
+	if err != nil {
+		return submissionError(err)
+	}
+
+	return c.JSON(http.StatusCreated, submission)
+}
+
+func (h *SubmissionHandler) GetMySubmissions(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	submissions, err := h.submissionService.GetUserSubmissions(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get submissions")
+	}
+
+	return c.JSON(http.StatusOK, submissions)
+}
+
+func (h *SubmissionHandler) GetQueue(c echo.Context) error {
+	items, err := h.submissionService.GetQueue(c.QueryParam("status"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get moderation queue")
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+// Approve publishes the submission; the body may carry corrections to any field
+func (h *SubmissionHandler) Approve(c echo.Context) error {
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid submission ID")
+	}
+
+	var req services.RiddleInput
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	moderatorID, _ := getUserID(c)
+
+	riddle, err := h.submissionService.Approve(moderatorID, uint(submissionID), req)
+	if err != nil {
+		return submissionError(err)
+	}
+
+	return c.JSON(http.StatusCreated, riddle)
+}
+
+func (h *SubmissionHandler) Reject(c echo.Context) error {
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid submission ID")
+	}
+
+	var req RejectSubmissionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	moderatorID, _ := getUserID(c)
+
+	submission, err := h.submissionService.Reject(moderatorID, uint(submissionID), req.Reason)
+	if err != nil {
+		return submissionError(err)
+	}
+
+	return c.JSON(http.StatusOK, submission)
+}
+
+func submissionError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrRiddleInvalid), errors.Is(err, services.ErrRejectionReasonRequired):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrSubmissionNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrSubmissionReviewed):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process submission")
+}
\ No newline at end of file
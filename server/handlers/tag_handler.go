@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TagHandler struct {
+	tagService services.TagService
+}
+
+func NewTagHandler(tagService services.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type RiddleTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// GetTags lists every tag with the number of riddles carrying it
+func (h *TagHandler) GetTags(c echo.Context) error {
+	tags, err := h.tagService.GetTags()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch tags")
+	}
+
+	return c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) CreateTag(c echo.Context) error {
+	var req TagRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	tag, err := h.tagService.CreateTag(req.Name)
+	if err != nil {
+		return tagError(err)
+	}
+
+	return c.JSON(http.StatusCreated, tag)
+}
+
+func (h *TagHandler) RenameTag(c echo.Context) error {
+	tagID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tag ID")
+	}
+
+	var req TagRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	tag, err := h.tagService.RenameTag(uint(tagID), req.Name)
+	if err != nil {
+		return tagError(err)
+	}
+
+	return c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) DeleteTag(c echo.Context) error {
+	tagID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tag ID")
+	}
+
+	if err := h.tagService.DeleteTag(uint(tagID)); err != nil {
+		return tagError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetRiddleTags replaces the riddle's tags, creating the missing ones
+func (h *TagHandler) SetRiddleTags(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req RiddleTagsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	tags, err := h.tagService.SetRiddleTags(uint(riddleID), req.Tags)
+	if err != nil {
+		return tagError(err)
+	}
+
+	return c.JSON(http.StatusOK, tags)
+}
+
+func tagError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrTagInvalid), errors.Is(err, services.ErrTooManyTags):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrTagNotFound), errors.Is(err, services.ErrRiddleNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrTagExists):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process tag")
+}
\ No newline at end of file
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TeamHandler struct {
+	teamService services.TeamService
+}
+
+func NewTeamHandler(teamService services.TeamService) *TeamHandler {
+	return &TeamHandler{
+		teamService: teamService,
+	}
+}
+
+type CreateTeamRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type TeamMemberRequest struct {
+	UserID uint `json:"user_id" validate:"required"`
+}
+
+func (h *TeamHandler) CreateTeam(c echo.Context) error {
+	var req CreateTeamRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	team, err := h.teamService.CreateTeam(userID, req.Name)
+	if err != nil {
+		return teamError(err)
+	}
+
+	return c.JSON(http.StatusCreated, team)
+}
+
+func (h *TeamHandler) GetTeam(c echo.Context) error {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid team ID")
+	}
+
+	team, err := h.teamService.GetTeam(uint(teamID))
+	if err != nil {
+		return teamError(err)
+	}
+
+	return c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) GetMyTeams(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	teams, err := h.teamService.GetUserTeams(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get teams")
+	}
+
+	return c.JSON(http.StatusOK, teams)
+}
+
+func (h *TeamHandler) AddMember(c echo.Context) error {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid team ID")
+	}
+
+	var req TeamMemberRequest
+	if err := c.Bind(&req); err != nil || req.UserID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	team, err := h.teamService.AddMember(userID, uint(teamID), req.UserID)
+	if err != nil {
+		return teamError(err)
+	}
+
+	return c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) RemoveMember(c echo.Context) error {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid team ID")
+	}
+
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	userID, _ := getUserID(c)
+
+	team, err := h.teamService.RemoveMember(userID, uint(teamID), uint(memberID))
+	if err != nil {
+		return teamError(err)
+	}
+
+	return c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) SetCaptain(c echo.Context) error {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid team ID")
+	}
+
+	var req TeamMemberRequest
+	if err := c.Bind(&req); err != nil || req.UserID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	userID, _ := getUserID(c)
+
+	team, err := h.teamService.SetCaptain(userID, uint(teamID), req.UserID)
+	if err != nil {
+		return teamError(err)
+	}
+
+	return c.JSON(http.StatusOK, team)
+}
+
+func teamError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrTeamNotFound), errors.Is(err, services.ErrTeamUserNotFound), errors.Is(err, services.ErrTeamMemberNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrNotTeamCaptain):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrTeamNameTaken), errors.Is(err, services.ErrTeamMemberExists), errors.Is(err, services.ErrCaptainCannotLeave),
+		errors.Is(err, services.ErrChgkPlayerRegistered):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrTeamNameInvalid):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process team")
+}
\ No newline at end of file
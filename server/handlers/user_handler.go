@@ -2,51 +2,88 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
+	"riddles-server/models"
 	"riddles-server/services"
 
 	"github.com/labstack/echo/v4"
 )
 
 type UserHandler struct {
-	userService services.UserService
+	userService        services.UserService
+	streakService      services.StreakService
+	scoringService     services.ScoringService
+	achievementService services.AchievementService
 }
 
-func NewUserHandler(userService services.UserService) *UserHandler {
+func NewUserHandler(
+	userService services.UserService,
+	streakService services.StreakService,
+	scoringService services.ScoringService,
+	achievementService services.AchievementService,
+) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:        userService,
+		streakService:      streakService,
+		scoringService:     scoringService,
+		achievementService: achievementService,
 	}
 }
 
 type UserStatsResponse struct {
-	TotalRiddles   int `json:"total_riddles"`
-	SolvedRiddles  int `json:"solved_riddles"`
-	SuccessRate    int `json:"success_rate"` // percentage
+	TotalRiddles  int    `json:"total_riddles"`
+	SolvedRiddles int    `json:"solved_riddles"`
+	SuccessRate   int    `json:"success_rate"` // percentage
+	CurrentStreak int    `json:"current_streak"`
+	LongestStreak int    `json:"longest_streak"`
+	FreezeTokens  int    `json:"freeze_tokens"`
+	XP            int    `json:"xp"`
+	Level         int    `json:"level"`
+	LevelName     string `json:"level_name"`
+	NextLevelXP   int    `json:"next_level_xp"`
+}
+
+type ProfileResponse struct {
+	*models.User
+	Score *services.ScoreInfo `json:"score"`
 }
 
 func (h *UserHandler) GetProfile(c echo.Context) error {
-	// TODO: Extract user ID from JWT token
-	// For now, we'll use a placeholder
-	userID := uint(1) // This should come from the authenticated user
+	userID, _ := getUserID(c)
 
 	user, err := h.userService.GetProfile(userID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "User not found")
 	}
 
-	return c.JSON(http.StatusOK, user)
+	score, err := h.scoringService.GetScore(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user score")
+	}
+
+	return c.JSON(http.StatusOK, ProfileResponse{
+		User:  user,
+		Score: score,
+	})
 }
 
 func (h *UserHandler) GetUserStats(c echo.Context) error {
-	// TODO: Extract user ID from JWT token
-	// For now, we'll use a placeholder
-	userID := uint(1) // This should come from the authenticated user
+	userID, _ := getUserID(c)
 
 	total, solved, err := h.userService.GetUserStats(userID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user stats")
 	}
 
+	streak, err := h.streakService.GetStreak(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user streak")
+	}
+
+	score, err := h.scoringService.GetScore(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user score")
+	}
+
 	successRate := 0
 	if total > 0 {
 		successRate = (solved * 100) / total
@@ -56,5 +93,45 @@ func (h *UserHandler) GetUserStats(c echo.Context) error {
 		TotalRiddles:  total,
 		SolvedRiddles: solved,
 		SuccessRate:   successRate,
+		CurrentStreak: streak.CurrentStreak,
+		LongestStreak: streak.LongestStreak,
+		FreezeTokens:  streak.FreezeTokens,
+		XP:            score.XP,
+		Level:         score.Level,
+		LevelName:     score.LevelName,
+		NextLevelXP:   score.NextLevelXP,
 	})
+}
+
+func (h *UserHandler) GetStreak(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	streak, err := h.streakService.GetStreak(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user streak")
+	}
+
+	return c.JSON(http.StatusOK, streak)
+}
+
+func (h *UserHandler) GetPointsLedger(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	entries, err := h.scoringService.GetLedger(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get points history")
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *UserHandler) GetAchievements(c echo.Context) error {
+	userID, _ := getUserID(c)
+
+	achievements, err := h.achievementService.GetUserAchievements(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get achievements")
+	}
+
+	return c.JSON(http.StatusOK, achievements)
 }
\ No newline at end of file
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WrongAnswerHandler struct {
+	wrongAnswerService services.WrongAnswerService
+}
+
+func NewWrongAnswerHandler(wrongAnswerService services.WrongAnswerService) *WrongAnswerHandler {
+	return &WrongAnswerHandler{
+		wrongAnswerService: wrongAnswerService,
+	}
+}
+
+type PromoteAnswerRequest struct {
+	Answer string `json:"answer" validate:"required"`
+}
+
+func (h *WrongAnswerHandler) GetTopWrongAnswers(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	// Missing or malformed values fall back to the default
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	report, err := h.wrongAnswerService.GetTopWrongAnswers(uint(riddleID), limit)
+	if err != nil {
+		return wrongAnswerError(err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// Promote accepts a wrong answer and credits the players who gave it
+func (h *WrongAnswerHandler) Promote(c echo.Context) error {
+	riddleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid riddle ID")
+	}
+
+	var req PromoteAnswerRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	editorID, _ := getUserID(c)
+
+	promotion, err := h.wrongAnswerService.Promote(editorID, uint(riddleID), req.Answer)
+	if err != nil {
+		return wrongAnswerError(err)
+	}
+
+	return c.JSON(http.StatusOK, promotion)
+}
+
+func wrongAnswerError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrWrongAnswerInvalid), errors.Is(err, services.ErrPromotionNotText):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRiddleNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrAnswerAccepted), errors.Is(err, services.ErrRiddleVersionConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process answers")
+}
\ No newline at end of file
@@ -1,10 +1,11 @@
+//go:build !seed && !rescore && !backfill && !promote
+
 package main
 
 import (
 	"log"
 	"riddles-server/database"
 	"riddles-server/routes"
-	"riddles-server/utils"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -15,13 +16,6 @@ func main() {
 	database.ConnectDB()
 	database.MigrateDB()
 
-	// Select daily riddles for today
-	if err := utils.SelectDailyRiddles(); err != nil {
-		log.Printf("Warning: Failed to select daily riddles: %v", err)
-	} else {
-		log.Println("Daily riddles selected successfully")
-	}
-
 	// Create Echo instance
 	e := echo.New()
 
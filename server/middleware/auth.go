@@ -2,12 +2,22 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
+	"riddles-server/models"
 	"riddles-server/services"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// UserIDKey is the echo context key holding the authenticated user's ID
+	UserIDKey = "user_id"
+	// UserRoleKey holds the user's role once RequireRole has checked it
+	UserRoleKey = "user_role"
+)
+
 type AuthMiddleware struct {
 	authService services.AuthService
 }
@@ -30,14 +40,75 @@ func (m *AuthMiddleware) AuthRequired(next echo.HandlerFunc) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid authorization header format")
 		}
 
-		_, err := m.authService.ValidateAccessToken(tokenString)
+		token, err := m.authService.ValidateAccessToken(tokenString)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired token")
 		}
 
-		// TODO: Extract user ID from token and store in context for use in handlers
-		// For now, we'll just validate the token
+		userID, ok := userIDFromToken(token)
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token claims")
+		}
+		c.Set(UserIDKey, userID)
 
 		return next(c)
 	}
-}
\ No newline at end of file
+}
+
+// AuthOptional stores the user ID in the context when a valid token is present,
+// but lets anonymous requests through
+func (m *AuthMiddleware) AuthOptional(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tokenString := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+		if tokenString == "" {
+			return next(c)
+		}
+
+		token, err := m.authService.ValidateAccessToken(tokenString)
+		if err == nil {
+			if userID, ok := userIDFromToken(token); ok {
+				c.Set(UserIDKey, userID)
+			}
+		}
+
+		return next(c)
+	}
+}
+
+// RequireRole lets through users with one of the given roles; admins are
+// always allowed. It must run after AuthRequired.
+func (m *AuthMiddleware) RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get(UserIDKey).(uint)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Authentication required")
+			}
+
+			role, err := m.authService.GetRole(userID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired token")
+			}
+			if role != models.RoleAdmin && !slices.Contains(roles, role) {
+				return echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions")
+			}
+			c.Set(UserRoleKey, role)
+
+			return next(c)
+		}
+	}
+}
+
+func userIDFromToken(token *jwt.Token) (uint, bool) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, false
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok || userID <= 0 {
+		return 0, false
+	}
+
+	return uint(userID), true
+}
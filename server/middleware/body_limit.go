@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"strconv"
+	"riddles-server/services"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// mediaUploadOverhead leaves room for the multipart headers around the file
+const mediaUploadOverhead = 64 << 10
+
+// MediaUploadLimit rejects upload bodies that can't hold an acceptable file
+// before the handler parses them into memory or temp files
+func MediaUploadLimit() echo.MiddlewareFunc {
+	return middleware.BodyLimit(strconv.Itoa(services.MaxMediaSize + mediaUploadOverhead))
+}
\ No newline at end of file
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// UserAchievement records a badge awarded to a user; badges themselves are defined in code
+type UserAchievement struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_achievement" json:"user_id"`
+	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Code      string    `gorm:"size:50;not null;uniqueIndex:idx_user_achievement" json:"code"`
+	AwardedAt time.Time `gorm:"not null" json:"awarded_at"`
+}
\ No newline at end of file
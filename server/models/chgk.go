@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// ChgkGame statuses
+const (
+	ChgkRegistration = "registration"
+	ChgkRunning      = "running"
+	ChgkFinished     = "finished"
+)
+
+// ChgkGame is a "Что? Где? Когда?" game: registered teams get a packet of
+// questions, each opened by the host for a timed discussion
+type ChgkGame struct {
+	ID              uint               `gorm:"primaryKey" json:"id"`
+	Title           string             `gorm:"size:255;not null" json:"title"`
+	HostID          uint               `gorm:"not null" json:"host_id"`
+	Host            User               `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Status          string             `gorm:"size:20;not null;default:registration" json:"status"`
+	CurrentPosition int                `gorm:"default:0" json:"current_position"` // 0 before the first question
+	Questions       []ChgkGameQuestion `gorm:"foreignKey:GameID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Teams           []ChgkGameTeam     `gorm:"foreignKey:GameID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+type ChgkGameQuestion struct {
+	ID       uint       `gorm:"primaryKey" json:"id"`
+	GameID   uint       `gorm:"not null;uniqueIndex:idx_chgk_game_position" json:"game_id"`
+	Position int        `gorm:"not null;uniqueIndex:idx_chgk_game_position" json:"position"`
+	RiddleID uint       `json:"riddle_id"`
+	Riddle   Riddle     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	OpenedAt *time.Time `json:"opened_at"`
+	ClosesAt *time.Time `json:"closes_at"` // answers are accepted until then
+}
+
+type ChgkGameTeam struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	GameID    uint      `gorm:"not null;uniqueIndex:idx_chgk_game_team" json:"game_id"`
+	TeamID    uint      `gorm:"not null;uniqueIndex:idx_chgk_game_team" json:"team_id"`
+	Team      Team      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"team"`
+	CreatedAt time.Time `json:"registered_at"`
+}
+
+// ChgkAnswer is the single answer a team gives to a question
+type ChgkAnswer struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	QuestionID  uint             `gorm:"not null;uniqueIndex:idx_chgk_answer_team" json:"question_id"`
+	Question    ChgkGameQuestion `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	TeamID      uint             `gorm:"not null;uniqueIndex:idx_chgk_answer_team" json:"team_id"`
+	Team        Team             `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Answer      string           `gorm:"type:text;not null" json:"answer"`
+	Correct     bool             `gorm:"default:false" json:"correct"`
+	SubmittedBy uint             `json:"submitted_by"`
+	SubmittedAt time.Time        `json:"submitted_at"`
+}
\ No newline at end of file
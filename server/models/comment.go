@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Comment is a message in a riddle's discussion thread. Deleted and removed
+// comments keep their row so replies stay in place.
+type Comment struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	RiddleID      uint       `gorm:"index;not null" json:"riddle_id"`
+	Riddle        Riddle     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID        uint       `gorm:"index;not null" json:"user_id"`
+	User          User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	ParentID      *uint      `gorm:"index" json:"parent_id"`
+	RootID        *uint      `gorm:"index" json:"root_id"` // top-level comment of the thread, nil for top-level comments
+	Depth         int        `gorm:"not null;default:0" json:"depth"`
+	Body          string     `gorm:"type:text;not null" json:"body"`
+	EditedAt      *time.Time `json:"edited_at"`
+	DeletedAt     *time.Time `json:"deleted_at"` // by the author
+	RemovedAt     *time.Time `json:"removed_at"` // by a moderator
+	RemovedByID   *uint      `json:"removed_by_id"`
+	RemovalReason string     `gorm:"type:text" json:"removal_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
\ No newline at end of file
@@ -8,6 +8,6 @@ type DailyRiddle struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
 	RiddleID     uint      `json:"riddle_id"`
 	Riddle       Riddle    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"riddle"`
-	FeaturedDate time.Time `json:"featured_date"`
+	FeaturedDate time.Time `gorm:"type:date" json:"featured_date"` // game day the riddle is featured on
 	CreatedAt    time.Time `json:"created_at"`
 }
\ No newline at end of file
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// Duel statuses
+const (
+	DuelPending  = "pending"
+	DuelAccepted = "accepted"
+	DuelDeclined = "declined"
+	DuelFinished = "finished"
+	DuelExpired  = "expired"
+)
+
+// Duel is an asynchronous 1v1 challenge over a frozen set of riddles;
+// each side plays it once at their own time
+type Duel struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	ChallengerID uint         `gorm:"index;not null" json:"challenger_id"`
+	Challenger   User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	OpponentID   uint         `gorm:"index;not null" json:"opponent_id"`
+	Opponent     User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Status       string       `gorm:"size:20;not null;default:pending" json:"status"`
+	ExpiresAt    time.Time    `gorm:"not null" json:"expires_at"`
+	WinnerID     *uint        `json:"winner_id"` // nil for a draw or an unfinished duel
+	Riddles      []DuelRiddle `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Plays        []DuelPlay   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+type DuelRiddle struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	DuelID   uint   `gorm:"not null;uniqueIndex:idx_duel_position" json:"duel_id"`
+	Position int    `gorm:"not null;uniqueIndex:idx_duel_position" json:"position"`
+	RiddleID uint   `json:"riddle_id"`
+	Riddle   Riddle `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+// DuelPlay is one side's single run through the duel's riddles
+type DuelPlay struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	DuelID     uint         `gorm:"not null;uniqueIndex:idx_duel_play" json:"duel_id"`
+	UserID     uint         `gorm:"not null;uniqueIndex:idx_duel_play" json:"user_id"`
+	StartedAt  time.Time    `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at"`
+	Answers    []DuelAnswer `gorm:"foreignKey:PlayID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"answers"`
+}
+
+// DuelAnswer tracks a riddle from the moment it is shown to the player
+type DuelAnswer struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	PlayID     uint       `gorm:"not null;uniqueIndex:idx_duel_answer" json:"play_id"`
+	Position   int        `gorm:"not null;uniqueIndex:idx_duel_answer" json:"position"`
+	ShownAt    time.Time  `gorm:"not null" json:"shown_at"`
+	AnsweredAt *time.Time `json:"answered_at"` // set once solved or given up
+	Attempts   int        `gorm:"default:0" json:"attempts"`
+	Solved     bool       `gorm:"default:false" json:"solved"`
+	DurationMs int64      `gorm:"default:0" json:"duration_ms"`
+}
\ No newline at end of file
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// LeaderboardScore is an incrementally maintained total for a board ("all", "category:<id>")
+type LeaderboardScore struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Board     string    `gorm:"size:50;not null;uniqueIndex:idx_leaderboard_board_user;index:idx_leaderboard_board_score,priority:1" json:"board"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_leaderboard_board_user" json:"user_id"`
+	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Score     int       `gorm:"not null;default:0;index:idx_leaderboard_board_score,priority:2,sort:desc" json:"score"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LeaderboardDay buckets scores per game day so rolling windows only sum a few rows per user
+type LeaderboardDay struct {
+	ID     uint      `gorm:"primaryKey" json:"id"`
+	Day    time.Time `gorm:"type:date;not null;uniqueIndex:idx_leaderboard_day_user" json:"day"`
+	UserID uint      `gorm:"not null;uniqueIndex:idx_leaderboard_day_user" json:"user_id"`
+	User   User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Score  int       `gorm:"not null;default:0" json:"score"`
+}
+
+// DailySpeedScore tracks how fast a user worked through the daily set of a game day
+type DailySpeedScore struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Day             time.Time `gorm:"type:date;not null;uniqueIndex:idx_daily_speed_day_user" json:"day"`
+	UserID          uint      `gorm:"not null;uniqueIndex:idx_daily_speed_day_user" json:"user_id"`
+	User            User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Solved          int       `gorm:"not null;default:0" json:"solved"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DurationSeconds int       `gorm:"not null;default:0" json:"duration_seconds"`
+}
\ No newline at end of file
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// RiddleMedia is an image attached to a riddle. The files live in the blob
+// store under Key and ThumbnailKey; the URLs are signed per response.
+type RiddleMedia struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	RiddleID     uint      `gorm:"index;not null" json:"riddle_id"`
+	Riddle       Riddle    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Key          string    `gorm:"size:255;not null;uniqueIndex" json:"-"`
+	ThumbnailKey string    `gorm:"size:255;not null" json:"-"`
+	ContentType  string    `gorm:"size:50;not null" json:"content_type"`
+	Size         int64     `gorm:"not null" json:"size"`
+	Width        int       `gorm:"not null" json:"width"`
+	Height       int       `gorm:"not null" json:"height"`
+	Position     int       `gorm:"not null;default:0" json:"position"`
+	UploadedByID *uint     `json:"uploaded_by_id,omitempty"`
+	UploadedBy   *User     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	URL          string `gorm:"-" json:"url,omitempty"`
+	ThumbnailURL string `gorm:"-" json:"thumbnail_url,omitempty"`
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification kinds
+const (
+	NotificationReportResolved = "report_resolved"
+)
+
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	User      User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Kind      string     `gorm:"size:30;not null" json:"kind"`
+	Message   string     `gorm:"type:text;not null" json:"message"`
+	Link      string     `gorm:"size:500" json:"link"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
\ No newline at end of file
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// PlacementTest is the adaptive onboarding sequence that estimates a new player's skill
+type PlacementTest struct {
+	ID              uint            `gorm:"primaryKey" json:"id"`
+	UserID          uint            `gorm:"index;not null" json:"user_id"`
+	User            User            `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	EstimatedRating float64         `gorm:"not null;default:1500" json:"estimated_rating"`
+	StartedAt       time.Time       `gorm:"not null" json:"started_at"`
+	FinishedAt      *time.Time      `json:"finished_at"`
+	Steps           []PlacementStep `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"steps"`
+}
+
+type PlacementStep struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	PlacementTestID uint       `gorm:"not null;uniqueIndex:idx_placement_step" json:"placement_test_id"`
+	Position        int        `gorm:"not null;uniqueIndex:idx_placement_step" json:"position"`
+	RiddleID        uint       `json:"riddle_id"`
+	Riddle          Riddle     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Answer          string     `gorm:"type:text" json:"answer"`
+	Correct         bool       `gorm:"default:false" json:"correct"`
+	AnsweredAt      *time.Time `json:"answered_at"`
+}
\ No newline at end of file
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// PointsEntry is a row of the append-only points ledger; a user's XP is the sum of their entries
+type PointsEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	RiddleID  *uint     `gorm:"index" json:"riddle_id"`         // kept without a constraint so the audit trail survives riddle deletion
+	Reason    string    `gorm:"size:30;not null" json:"reason"` // solve, adjustment
+	Points    int       `gorm:"not null" json:"points"`
+	Details   string    `gorm:"type:text" json:"details"` // JSON breakdown of the calculation
+	CreatedAt time.Time `json:"created_at"`
+}
\ No newline at end of file
@@ -5,13 +5,18 @@ import (
 )
 
 type UserRiddleProgress struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `json:"user_id"`
-	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user"`
-	RiddleID  uint      `json:"riddle_id"`
-	Riddle    Riddle    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"riddle"`
-	Solved    bool      `gorm:"default:false" json:"solved"`
-	SolvedAt  time.Time `json:"solved_at"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `json:"user_id"`
+	User       User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user"`
+	RiddleID   uint       `json:"riddle_id"`
+	Riddle     Riddle     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"riddle"`
+	StartedAt  *time.Time `json:"started_at"` // when the player first opened the riddle
+	Solved     bool       `gorm:"default:false" json:"solved"`
+	SolvedAt   time.Time  `json:"solved_at"`
+	Attempts   int        `gorm:"default:0" json:"attempts"` // answers submitted until solved
+	HintsUsed  int        `gorm:"default:0" json:"hints_used"`
+	Revealed   bool       `gorm:"default:false" json:"revealed"` // the answer was shown before solving
+	RevealedAt *time.Time `json:"revealed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
\ No newline at end of file
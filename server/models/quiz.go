@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// QuizSession is a timed set of riddles; the deadline is enforced server-side
+type QuizSession struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	UserID           uint           `gorm:"index;not null" json:"user_id"`
+	User             User           `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CategoryID       *uint          `json:"category_id"`
+	Difficulty       string         `gorm:"size:20" json:"difficulty"`
+	TimeLimitSeconds int            `gorm:"not null" json:"time_limit_seconds"`
+	StartedAt        time.Time      `gorm:"not null" json:"started_at"`
+	ExpiresAt        time.Time      `gorm:"not null" json:"expires_at"`
+	FinishedAt       *time.Time     `json:"finished_at"`
+	Score            int            `gorm:"default:0" json:"score"`
+	Questions        []QuizQuestion `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"questions"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+type QuizQuestion struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	QuizSessionID uint       `gorm:"index;not null" json:"quiz_session_id"`
+	Position      int        `gorm:"not null" json:"position"`
+	RiddleID      uint       `json:"riddle_id"`
+	Riddle        Riddle     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"riddle"`
+	Answer        string     `gorm:"type:text" json:"answer"` // the player's answer
+	Correct       bool       `gorm:"default:false" json:"correct"`
+	AnsweredAt    *time.Time `json:"answered_at"`
+}
\ No newline at end of file
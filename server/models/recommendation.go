@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// Recommendation is a precomputed "you might like" entry. UserID 0 holds the
+// popularity list used for anonymous and new players.
+type Recommendation struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_recommendation_user_riddle" json:"user_id"`
+	RiddleID   uint      `gorm:"not null;uniqueIndex:idx_recommendation_user_riddle" json:"riddle_id"`
+	Riddle     Riddle    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"riddle"`
+	Score      float64   `gorm:"not null" json:"score"`
+	Reason     string    `gorm:"size:20;not null" json:"reason"` // similar, affinity, popular
+	ComputedAt time.Time `gorm:"not null" json:"computed_at"`
+}
\ No newline at end of file
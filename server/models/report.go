@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// Report reasons
+const (
+	ReportWrongAnswer = "wrong_answer"
+	ReportDuplicate   = "duplicate"
+	ReportOffensive   = "offensive"
+	ReportTypo        = "typo"
+)
+
+// Report statuses
+const (
+	ReportOpen      = "open"
+	ReportFixed     = "fixed"
+	ReportDismissed = "dismissed"
+)
+
+// RiddleReport is a player's complaint about a riddle
+type RiddleReport struct {
+	ID           uint              `gorm:"primaryKey" json:"id"`
+	RiddleID     uint              `gorm:"index;not null" json:"riddle_id"`
+	Riddle       Riddle            `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	ReporterID   uint              `gorm:"index;not null" json:"reporter_id"`
+	Reporter     User              `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Reason       string            `gorm:"size:20;not null" json:"reason"`
+	Comment      string            `gorm:"type:text" json:"comment"`
+	Status       string            `gorm:"size:20;not null;index;default:open" json:"status"`
+	ResolutionID *uint             `gorm:"index" json:"resolution_id"`
+	Resolution   *ReportResolution `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"resolution,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// ReportResolution closes every open report on a riddle at once
+type ReportResolution struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	RiddleID    uint      `gorm:"index;not null" json:"riddle_id"`
+	ModeratorID uint      `gorm:"not null" json:"moderator_id"`
+	Outcome     string    `gorm:"size:20;not null" json:"outcome"` // ReportFixed or ReportDismissed
+	Note        string    `gorm:"type:text" json:"note"`
+	FixLink     string    `gorm:"size:500" json:"fix_link"` // where the correction can be seen
+	CreatedAt   time.Time `json:"created_at"`
+	// Reporters who couldn't be notified; the reports stay resolved, see the server log
+	FailedNotifications int `gorm:"-" json:"failed_notifications"`
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// ReviewItem schedules a riddle the user failed or revealed for spaced
+// repetition (SM-2)
+type ReviewItem struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UserID         uint       `gorm:"not null;uniqueIndex:idx_review_user_riddle" json:"user_id"`
+	User           User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	RiddleID       uint       `gorm:"not null;uniqueIndex:idx_review_user_riddle" json:"riddle_id"`
+	Riddle         Riddle     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	EaseFactor     float64    `gorm:"not null;default:2.5" json:"ease_factor"`
+	IntervalDays   int        `gorm:"not null;default:0" json:"interval_days"`
+	Repetitions    int        `gorm:"not null;default:0" json:"repetitions"` // successful reviews in a row
+	DueDate        time.Time  `gorm:"type:date;index;not null" json:"due_date"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
\ No newline at end of file
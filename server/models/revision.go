@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Revision actions
+const (
+	RevisionInitial  = "initial" // state before the first recorded edit
+	RevisionCreate   = "create"
+	RevisionEdit     = "edit"
+	RevisionRollback = "rollback"
+	RevisionStatus   = "status" // status change, including scheduled publishing
+)
+
+// RiddleRevision is an immutable record of a riddle's content at one version
+type RiddleRevision struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	RiddleID   uint      `gorm:"uniqueIndex:idx_riddle_revision;not null" json:"riddle_id"`
+	Riddle     Riddle    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Version    int       `gorm:"uniqueIndex:idx_riddle_revision;not null" json:"version"`
+	AuthorID   *uint     `json:"author_id"` // nil for the initial revision
+	Author     *User     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	Action     string    `gorm:"size:20;not null" json:"action"`
+	Snapshot   string    `gorm:"type:text;not null" json:"-"` // JSON of the riddle's content and status
+	Diff       string    `gorm:"type:text;not null" json:"-"` // JSON of the changed fields
+	RestoredTo *int      `json:"restored_to,omitempty"`       // version a rollback restored
+	CreatedAt  time.Time `json:"created_at"`
+}
\ No newline at end of file
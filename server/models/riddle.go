@@ -1,24 +1,143 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Riddle statuses; only published riddles are shown to players
+const (
+	RiddleDraft     = "draft"
+	RiddleScheduled = "scheduled" // published automatically at PublishAt
+	RiddlePublished = "published"
+	RiddleArchived  = "archived"
 )
 
+// Riddle types; text riddles are answered in words, the others through Payload
+const (
+	RiddleText           = "text"
+	RiddleSingleChoice   = "single_choice"   // one correct option
+	RiddleMultipleChoice = "multiple_choice" // one or more correct options, all must be picked
+	RiddleOrdering       = "ordering"        // the options put in the right order
+	RiddleNumeric        = "numeric"         // a number within Key.Min..Key.Max
+	RiddleTrueFalse      = "true_false"
+)
+
+type RiddleOption struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// RiddlePayload is the type-specific part of a riddle that players see
+type RiddlePayload struct {
+	Options []RiddleOption `json:"options,omitempty"` // choice and ordering riddles, in display order
+	Unit    string         `json:"unit,omitempty"`    // numeric riddles
+}
+
+// RiddleKey holds the correct answer of a typed riddle and never leaves the server
+type RiddleKey struct {
+	Correct []string `json:"correct,omitempty"` // option IDs; for ordering riddles, all of them in the right order
+	Min     *float64 `json:"min,omitempty"`     // numeric riddles, inclusive
+	Max     *float64 `json:"max,omitempty"`
+	Truth   *bool    `json:"truth,omitempty"` // true/false riddles
+}
+
 type Riddle struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	Title           string         `gorm:"size:255;not null" json:"title"`
+	Description     string         `gorm:"type:text;not null" json:"description"`
+	Answer          string         `gorm:"type:text;not null" json:"answer"`
+	AcceptedAnswers string         `gorm:"type:text;not null;default:''" json:"-"`           // other accepted answers, one per line
+	Explanation     string         `gorm:"type:text;not null;default:''" json:"explanation"` // shown with the answer
+	CategoryID      uint           `json:"category_id"`
+	Category        Category       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"category"`
+	Difficulty      string         `gorm:"size:20;not null" json:"difficulty"` // easy, medium, hard
+	Type            string         `gorm:"size:20;not null;default:text" json:"type"`
+	Payload         RiddlePayload  `gorm:"type:text" json:"payload"`
+	Key             RiddleKey      `gorm:"type:text" json:"-"`
+	Media           []RiddleMedia  `json:"media,omitempty"` // loaded for player-facing lookups only
+	Tags            []Tag          `gorm:"many2many:riddle_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"tags"`
+	Rating          float64        `gorm:"not null;default:1500" json:"rating"` // Elo; a riddle "wins" when players fail it
+	RatingGames     int            `gorm:"not null;default:0" json:"rating_games"`
+	RatedDifficulty string         `gorm:"size:20;not null;default:''" json:"rated_difficulty,omitempty"` // bucket of Rating, set by recalibration
+	Version         int            `gorm:"not null;default:1" json:"version"`                             // bumped on every edit, for optimistic locking
+	Status          string         `gorm:"size:20;not null;default:published;index" json:"status"`
+	PublishAt       *time.Time     `json:"publish_at,omitempty"`
+	AuthorID        *uint          `json:"author_id,omitempty"` // set for riddles submitted by players
+	AuthorName      string         `gorm:"size:50" json:"author_name,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"` // soft delete keeps player history
+}
+
+// Category groups riddles; categories nest through ParentID
+type Category struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
-	Title       string    `gorm:"size:255;not null" json:"title"`
-	Description string    `gorm:"type:text;not null" json:"description"`
-	Answer      string    `gorm:"type:text;not null" json:"answer"`
-	CategoryID  uint      `json:"category_id"`
-	Category    Category  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"category"`
-	Difficulty  string    `gorm:"size:20;not null" json:"difficulty"` // easy, medium, hard
+	Name        string    `gorm:"size:50;not null;unique" json:"name"`
+	Slug        string    `gorm:"size:100;uniqueIndex" json:"slug"` // transliterated from the name unless set
+	ParentID    *uint     `gorm:"index" json:"parent_id"`
+	Parent      *Category `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	Description string    `gorm:"type:text" json:"description"`
+	Icon        string    `gorm:"size:100" json:"icon"` // emoji or icon name
+	SortOrder   int       `gorm:"not null;default:0" json:"sort_order"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-type Category struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `gorm:"size:50;not null;unique" json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+// DisplayedDifficulty is what players see: the bucket the riddle's rating
+// falls into once it has enough results, the editor's choice until then.
+// Points and leaderboard weights always follow the editor's Difficulty.
+func (riddle *Riddle) DisplayedDifficulty() string {
+	if riddle.RatedDifficulty != "" {
+		return riddle.RatedDifficulty
+	}
+	return riddle.Difficulty
+}
+
+func (payload RiddlePayload) Value() (driver.Value, error) {
+	return marshalColumn(payload)
+}
+
+func (payload *RiddlePayload) Scan(value interface{}) error {
+	return unmarshalColumn(value, payload)
+}
+
+func (key RiddleKey) Value() (driver.Value, error) {
+	return marshalColumn(key)
+}
+
+func (key *RiddleKey) Scan(value interface{}) error {
+	return unmarshalColumn(value, key)
+}
+
+// marshalColumn stores a value as JSON text
+func marshalColumn(value interface{}) (driver.Value, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// unmarshalColumn reads JSON text; rows from before the column existed are NULL
+func unmarshalColumn(value interface{}, dst interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported JSON column value %T", value)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dst)
 }
\ No newline at end of file
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+type UserStreak struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UserID         uint       `gorm:"uniqueIndex" json:"user_id"`
+	User           User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CurrentStreak  int        `gorm:"default:0" json:"current_streak"`
+	LongestStreak  int        `gorm:"default:0" json:"longest_streak"`
+	FreezeTokens   int        `gorm:"default:0" json:"freeze_tokens"`
+	LastActiveDate *time.Time `gorm:"type:date" json:"last_active_date"` // last game day with a solved daily riddle
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
\ No newline at end of file
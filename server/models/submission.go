@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// RiddleSubmission statuses
+const (
+	SubmissionPending  = "pending"
+	SubmissionApproved = "approved"
+	SubmissionRejected = "rejected"
+)
+
+// RiddleSubmission is a riddle proposed by a player; it only becomes a
+// Riddle once a moderator approves it
+type RiddleSubmission struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	AuthorID        uint       `gorm:"index;not null" json:"author_id"`
+	Author          User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Title           string     `gorm:"size:255;not null" json:"title"`
+	Description     string     `gorm:"type:text;not null" json:"description"`
+	Answer          string     `gorm:"type:text;not null" json:"answer"`
+	Explanation     string     `gorm:"type:text;not null;default:''" json:"explanation"` // why the answer is right
+	CategoryID      uint       `json:"category_id"`
+	Category        Category   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"category"`
+	Difficulty      string     `gorm:"size:20;not null" json:"difficulty"`
+	Status          string     `gorm:"size:20;not null;default:pending;index" json:"status"`
+	ModeratorID     *uint      `json:"moderator_id"`
+	RejectionReason string     `gorm:"type:text" json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	RiddleID        *uint      `json:"riddle_id"` // the published riddle
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
\ No newline at end of file
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// Tag labels riddles across categories. Key is the normalized name, so
+// "Логика" and "логика" are the same tag.
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:50;not null" json:"name"`
+	Key       string    `gorm:"size:50;not null;uniqueIndex" json:"key"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+type Team struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	Name      string       `gorm:"size:100;not null;unique" json:"name"`
+	CaptainID uint         `gorm:"not null" json:"captain_id"`
+	Captain   User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Members   []TeamMember `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"members"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type TeamMember struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TeamID    uint      `gorm:"not null;uniqueIndex:idx_team_member" json:"team_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_team_member" json:"user_id"`
+	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CreatedAt time.Time `json:"joined_at"`
+}
\ No newline at end of file
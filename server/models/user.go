@@ -4,11 +4,25 @@ import (
 	"time"
 )
 
+// User roles
+const (
+	RoleUser      = "user"
+	RoleEditor    = "editor"    // manages riddle content
+	RoleModerator = "moderator" // reviews user submissions and comments
+	RoleAdmin     = "admin"     // allowed everything
+)
+
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Username  string    `gorm:"size:50;not null;unique" json:"username"`
-	Email     string    `gorm:"size:100;not null;unique" json:"email"`
-	Password  string    `gorm:"size:255;not null" json:"password"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	Username            string     `gorm:"size:50;not null;unique" json:"username"`
+	Email               string     `gorm:"size:100;not null;unique" json:"email"`
+	Password            string     `gorm:"size:255;not null" json:"password"`
+	Role                string     `gorm:"size:20;not null;default:user" json:"role"`
+	Rating              float64    `gorm:"not null;default:1500" json:"rating"` // Elo skill rating
+	RatingGames         int        `gorm:"not null;default:0" json:"rating_games"`
+	SkillLevel          string     `gorm:"size:20" json:"skill_level"` // from the placement test: beginner, intermediate, advanced
+	PreferredDifficulty string     `gorm:"size:20" json:"preferred_difficulty"`
+	PlacedAt            *time.Time `json:"placed_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
\ No newline at end of file
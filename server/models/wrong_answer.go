@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// WrongAnswer is an incorrect answer a player submitted to a riddle they hadn't solved yet
+type WrongAnswer struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	RiddleID   uint      `gorm:"index:idx_wrong_answer_riddle;not null" json:"riddle_id"`
+	Riddle     Riddle    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID     uint      `gorm:"index;not null" json:"user_id"`
+	User       User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Answer     string    `gorm:"type:text;not null" json:"answer"`
+	Normalized string    `gorm:"index:idx_wrong_answer_riddle;type:text;not null" json:"normalized"`
+	Attempt    int       `gorm:"not null" json:"attempt"` // the player's attempt number for this riddle
+	CreatedAt  time.Time `json:"created_at"`
+}
\ No newline at end of file
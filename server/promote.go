@@ -0,0 +1,41 @@
+//go:build promote
+
+package main
+
+import (
+	"log"
+	"os"
+	"slices"
+	"riddles-server/database"
+	"riddles-server/models"
+	"riddles-server/repository"
+)
+
+// Sets a user's role: go run -tags promote . <email> <user|editor|moderator|admin>
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatal("Usage: promote <email> <role>")
+	}
+	email, role := os.Args[1], os.Args[2]
+
+	roles := []string{models.RoleUser, models.RoleEditor, models.RoleModerator, models.RoleAdmin}
+	if !slices.Contains(roles, role) {
+		log.Fatalf("Unknown role %q, expected one of %v", role, roles)
+	}
+
+	// Initialize database
+	database.ConnectDB()
+	database.MigrateDB()
+
+	userRepo := repository.NewUserRepository()
+	user, err := userRepo.FindByEmail(email)
+	if err != nil {
+		log.Fatal("User not found:", err)
+	}
+
+	if err := userRepo.SetRole(user.ID, role); err != nil {
+		log.Fatal("Failed to set role:", err)
+	}
+
+	log.Printf("User %s is now %s", user.Username, role)
+}
\ No newline at end of file
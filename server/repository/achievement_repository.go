@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm/clause"
+)
+
+type AchievementRepository interface {
+	Award(achievement *models.UserAchievement) error
+	FindByUserID(userID uint) ([]models.UserAchievement, error)
+	CountSolved(userID uint, difficulty string) (int, error) // all difficulties when empty
+	CountNoHintSolves(userID uint) (int, error)
+	GetCategoryCompletion(userID uint, categoryName string) (int, int, error) // solved, total
+	CountRatings(userID uint) (int, error)
+}
+
+type achievementRepository struct{}
+
+func NewAchievementRepository() AchievementRepository {
+	return &achievementRepository{}
+}
+
+func (r *achievementRepository) Award(achievement *models.UserAchievement) error {
+	// Awarding twice keeps the original timestamp
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(achievement).Error
+}
+
+func (r *achievementRepository) FindByUserID(userID uint) ([]models.UserAchievement, error) {
+	var achievements []models.UserAchievement
+	err := database.DB.Where("user_id = ?", userID).Order("awarded_at").Find(&achievements).Error
+	return achievements, err
+}
+
+func (r *achievementRepository) CountSolved(userID uint, difficulty string) (int, error) {
+	var count int64
+	query := database.DB.Model(&models.UserRiddleProgress{}).
+		Joins("JOIN riddles ON riddles.id = user_riddle_progresses.riddle_id").
+		Where("user_riddle_progresses.user_id = ? AND user_riddle_progresses.solved = ?", userID, true)
+	if difficulty != "" {
+		query = query.Where("riddles.difficulty = ?", difficulty)
+	}
+	err := query.Count(&count).Error
+	return int(count), err
+}
+
+func (r *achievementRepository) CountNoHintSolves(userID uint) (int, error) {
+	var count int64
+	err := database.DB.Model(&models.UserRiddleProgress{}).Where("user_id = ? AND solved = ? AND hints_used = 0", userID, true).Count(&count).Error
+	return int(count), err
+}
+
+func (r *achievementRepository) GetCategoryCompletion(userID uint, categoryName string) (int, int, error) {
+	var total, solved int64
+
+	err := database.DB.Model(&models.Riddle{}).
+		Joins("JOIN categories ON categories.id = riddles.category_id").
+		Where("categories.name = ?", categoryName).
+		Scopes(published).
+		Count(&total).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = database.DB.Model(&models.UserRiddleProgress{}).
+		Joins("JOIN riddles ON riddles.id = user_riddle_progresses.riddle_id").
+		Joins("JOIN categories ON categories.id = riddles.category_id").
+		Where("user_riddle_progresses.user_id = ? AND user_riddle_progresses.solved = ? AND categories.name = ?", userID, true, categoryName).
+		Scopes(published).
+		Count(&solved).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(solved), int(total), nil
+}
+
+func (r *achievementRepository) CountRatings(userID uint) (int, error) {
+	var count int64
+	err := database.DB.Model(&models.RiddleRating{}).Where("user_id = ?", userID).Count(&count).Error
+	return int(count), err
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+)
+
+type CategoryRepository interface {
+	FindAll() ([]models.Category, error) // in display order
+	FindByID(id uint) (*models.Category, error)
+	FindByName(name string) (*models.Category, error)
+	FindBySlug(slug string) (*models.Category, error)
+	CountRiddles() (map[uint]int, error) // published riddles directly in each category
+	Create(category *models.Category) error
+	Update(category *models.Category) error
+}
+
+type categoryRepository struct{}
+
+func NewCategoryRepository() CategoryRepository {
+	return &categoryRepository{}
+}
+
+func (r *categoryRepository) FindAll() ([]models.Category, error) {
+	var categories []models.Category
+	err := database.DB.Order("sort_order, name").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) FindByID(id uint) (*models.Category, error) {
+	var category models.Category
+	err := database.DB.First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindByName(name string) (*models.Category, error) {
+	var category models.Category
+	err := database.DB.Where("name = ?", name).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	err := database.DB.Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) CountRiddles() (map[uint]int, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int
+	}
+	err := database.DB.Model(&models.Riddle{}).Scopes(published).Select("category_id, COUNT(*) AS count").Group("category_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *categoryRepository) Create(category *models.Category) error {
+	return database.DB.Omit("Parent").Create(category).Error
+}
+
+func (r *categoryRepository) Update(category *models.Category) error {
+	return database.DB.Model(category).
+		Select("name", "slug", "parent_id", "description", "icon", "sort_order").
+		Updates(category).Error
+}
\ No newline at end of file
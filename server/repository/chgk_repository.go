@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChgkRepository interface {
+	Create(game *models.ChgkGame) error
+	FindByID(id uint) (*models.ChgkGame, error)
+	RegisterTeam(gameTeam *models.ChgkGameTeam) (bool, error) // false when already registered
+	OpenQuestion(game *models.ChgkGame, position int, openedAt, closesAt time.Time) (bool, error)
+	SaveAnswer(answer *models.ChgkAnswer) (bool, error) // false when the team already answered
+	FindAnswers(gameID uint) ([]models.ChgkAnswer, error)
+	Finish(game *models.ChgkGame) error
+}
+
+type chgkRepository struct{}
+
+func NewChgkRepository() ChgkRepository {
+	return &chgkRepository{}
+}
+
+func (r *chgkRepository) Create(game *models.ChgkGame) error {
+	return database.DB.Create(game).Error
+}
+
+func (r *chgkRepository) FindByID(id uint) (*models.ChgkGame, error) {
+	var game models.ChgkGame
+	err := database.DB.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Preload("Questions.Riddle", includingDeleted).Preload("Questions.Riddle.Media", mediaOrder).
+		Preload("Teams", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).Preload("Teams.Team.Members").
+		First(&game, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func (r *chgkRepository) RegisterTeam(gameTeam *models.ChgkGameTeam) (bool, error) {
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(gameTeam)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *chgkRepository) OpenQuestion(game *models.ChgkGame, position int, openedAt, closesAt time.Time) (bool, error) {
+	opened := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// The current_position guard lets only one of two concurrent "next" calls through
+		result := tx.Model(&models.ChgkGame{}).
+			Where("id = ? AND current_position = ?", game.ID, position-1).
+			Updates(map[string]interface{}{
+				"current_position": position,
+				"status":           models.ChgkRunning,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		opened = true
+		return tx.Model(&models.ChgkGameQuestion{}).
+			Where("game_id = ? AND position = ?", game.ID, position).
+			Updates(map[string]interface{}{
+				"opened_at": openedAt,
+				"closes_at": closesAt,
+			}).Error
+	})
+	return opened, err
+}
+
+func (r *chgkRepository) SaveAnswer(answer *models.ChgkAnswer) (bool, error) {
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(answer)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *chgkRepository) FindAnswers(gameID uint) ([]models.ChgkAnswer, error) {
+	var answers []models.ChgkAnswer
+	err := database.DB.
+		Joins("JOIN chgk_game_questions ON chgk_game_questions.id = chgk_answers.question_id").
+		Where("chgk_game_questions.game_id = ?", gameID).
+		Find(&answers).Error
+	return answers, err
+}
+
+func (r *chgkRepository) Finish(game *models.ChgkGame) error {
+	return database.DB.Model(game).
+		Where("status <> ?", models.ChgkFinished).
+		Update("status", models.ChgkFinished).Error
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	FindByID(id uint) (*models.Comment, error)
+	FindRoots(riddleID uint, offset, limit int) ([]models.Comment, int, error) // page of top-level comments, total count
+	FindReplies(rootIDs []uint) ([]models.Comment, error)
+	UpdateBody(id uint, body string, editedAt time.Time) error
+	MarkDeleted(id uint, deletedAt time.Time) error
+	MarkRemoved(id, moderatorID uint, reason string, removedAt time.Time) error
+}
+
+type commentRepository struct{}
+
+func NewCommentRepository() CommentRepository {
+	return &commentRepository{}
+}
+
+func (r *commentRepository) Create(comment *models.Comment) error {
+	return database.DB.Omit("Riddle", "User").Create(comment).Error
+}
+
+func (r *commentRepository) FindByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	err := database.DB.Preload("User").First(&comment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepository) FindRoots(riddleID uint, offset, limit int) ([]models.Comment, int, error) {
+	query := database.DB.Model(&models.Comment{}).Where("riddle_id = ? AND parent_id IS NULL", riddleID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []models.Comment
+	err := query.Preload("User").Order("created_at, id").Offset(offset).Limit(limit).Find(&comments).Error
+	return comments, int(total), err
+}
+
+func (r *commentRepository) FindReplies(rootIDs []uint) ([]models.Comment, error) {
+	var comments []models.Comment
+	if len(rootIDs) == 0 {
+		return comments, nil
+	}
+	err := database.DB.Preload("User").Where("root_id IN ?", rootIDs).Order("created_at, id").Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) UpdateBody(id uint, body string, editedAt time.Time) error {
+	return database.DB.Model(&models.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"body":      body,
+		"edited_at": editedAt,
+	}).Error
+}
+
+func (r *commentRepository) MarkDeleted(id uint, deletedAt time.Time) error {
+	return database.DB.Model(&models.Comment{}).Where("id = ?", id).Update("deleted_at", deletedAt).Error
+}
+
+func (r *commentRepository) MarkRemoved(id, moderatorID uint, reason string, removedAt time.Time) error {
+	return database.DB.Model(&models.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"removed_at":     removedAt,
+		"removed_by_id":  moderatorID,
+		"removal_reason": reason,
+	}).Error
+}
\ No newline at end of file
@@ -4,13 +4,18 @@ import (
 	"time"
 	"riddles-server/database"
 	"riddles-server/models"
+	"riddles-server/utils"
+
+	"gorm.io/gorm"
 )
 
 type DailyRiddleRepository interface {
 	Create(dailyRiddle *models.DailyRiddle) error
 	FindByDate(date time.Time) (*models.DailyRiddle, error)
 	FindToday() (*models.DailyRiddle, error)
+	FindAllByDate(date time.Time) ([]models.DailyRiddle, error)
 	GetRiddlesForDateRange(startDate, endDate time.Time) ([]models.DailyRiddle, error)
+	IsFeatured(riddleID uint, date time.Time) (bool, error)
 }
 
 type dailyRiddleRepository struct{}
@@ -19,22 +24,39 @@ func NewDailyRiddleRepository() DailyRiddleRepository {
 	return &dailyRiddleRepository{}
 }
 
+// featuredPublished skips days whose riddle was unpublished or deleted since
+func featuredPublished(db *gorm.DB) *gorm.DB {
+	return db.Where("riddle_id IN (?)", database.DB.Model(&models.Riddle{}).Scopes(published).Select("id"))
+}
+
 func (r *dailyRiddleRepository) Create(dailyRiddle *models.DailyRiddle) error {
 	return database.DB.Create(dailyRiddle).Error
 }
 
 func (r *dailyRiddleRepository) FindByDate(date time.Time) (*models.DailyRiddle, error) {
 	var dailyRiddle models.DailyRiddle
-	err := database.DB.Preload("Riddle.Category").Where("featured_date = ?", date.Format("2006-01-02")).First(&dailyRiddle).Error
+	err := database.DB.Scopes(featuredPublished).Preload("Riddle.Category").Preload("Riddle.Media", mediaOrder).Where("featured_date = ?", date.Format("2006-01-02")).First(&dailyRiddle).Error
 	return &dailyRiddle, err
 }
 
 func (r *dailyRiddleRepository) FindToday() (*models.DailyRiddle, error) {
-	return r.FindByDate(time.Now())
+	return r.FindByDate(utils.GameToday())
+}
+
+func (r *dailyRiddleRepository) FindAllByDate(date time.Time) ([]models.DailyRiddle, error) {
+	var dailyRiddles []models.DailyRiddle
+	err := database.DB.Scopes(featuredPublished).Preload("Riddle.Category").Where("featured_date = ?", date.Format("2006-01-02")).Order("id").Find(&dailyRiddles).Error
+	return dailyRiddles, err
 }
 
 func (r *dailyRiddleRepository) GetRiddlesForDateRange(startDate, endDate time.Time) ([]models.DailyRiddle, error) {
 	var dailyRiddles []models.DailyRiddle
-	err := database.DB.Preload("Riddle.Category").Where("featured_date BETWEEN ? AND ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).Find(&dailyRiddles).Error
+	err := database.DB.Scopes(featuredPublished).Preload("Riddle.Category").Where("featured_date BETWEEN ? AND ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).Order("featured_date, id").Find(&dailyRiddles).Error
 	return dailyRiddles, err
+}
+
+func (r *dailyRiddleRepository) IsFeatured(riddleID uint, date time.Time) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.DailyRiddle{}).Where("riddle_id = ? AND featured_date = ?", riddleID, date.Format("2006-01-02")).Count(&count).Error
+	return count > 0, err
 }
\ No newline at end of file
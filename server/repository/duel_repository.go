@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DuelRepository interface {
+	Create(duel *models.Duel) error
+	FindByID(id uint) (*models.Duel, error)
+	FindByUserID(userID uint, status string) ([]models.Duel, error) // all statuses when empty
+	UpdateStatus(duel *models.Duel, from []string) (bool, error)    // false when the status already moved on
+	StartPlay(play *models.DuelPlay) (bool, error)                  // false when the user already started
+	RecordAttempt(answerID uint) (bool, error)
+	CloseAnswer(answerID uint, solved bool, answeredAt time.Time, durationMs int64) (bool, error)
+	ShowRiddle(answer *models.DuelAnswer) error
+	FinishPlay(playID uint, finishedAt time.Time) error
+}
+
+type duelRepository struct{}
+
+func NewDuelRepository() DuelRepository {
+	return &duelRepository{}
+}
+
+func (r *duelRepository) Create(duel *models.Duel) error {
+	return database.DB.Create(duel).Error
+}
+
+func (r *duelRepository) FindByID(id uint) (*models.Duel, error) {
+	var duel models.Duel
+	err := r.withDetails(database.DB).First(&duel, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &duel, nil
+}
+
+func (r *duelRepository) FindByUserID(userID uint, status string) ([]models.Duel, error) {
+	var duels []models.Duel
+	query := r.withDetails(database.DB).Where("challenger_id = ? OR opponent_id = ?", userID, userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&duels).Error
+	return duels, err
+}
+
+func (r *duelRepository) UpdateStatus(duel *models.Duel, from []string) (bool, error) {
+	result := database.DB.Model(&models.Duel{}).
+		Where("id = ? AND status IN ?", duel.ID, from).
+		Updates(map[string]interface{}{
+			"status":    duel.Status,
+			"winner_id": duel.WinnerID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *duelRepository) StartPlay(play *models.DuelPlay) (bool, error) {
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Omit("Answers").Create(play)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	if len(play.Answers) == 0 {
+		return true, nil
+	}
+	for i := range play.Answers {
+		play.Answers[i].PlayID = play.ID
+	}
+	return true, database.DB.Create(&play.Answers).Error
+}
+
+func (r *duelRepository) RecordAttempt(answerID uint) (bool, error) {
+	result := database.DB.Model(&models.DuelAnswer{}).
+		Where("id = ? AND answered_at IS NULL", answerID).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *duelRepository) CloseAnswer(answerID uint, solved bool, answeredAt time.Time, durationMs int64) (bool, error) {
+	// The answered_at guard makes concurrent submissions close a riddle once
+	result := database.DB.Model(&models.DuelAnswer{}).
+		Where("id = ? AND answered_at IS NULL", answerID).
+		Updates(map[string]interface{}{
+			"solved":      solved,
+			"answered_at": answeredAt,
+			"duration_ms": durationMs,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *duelRepository) ShowRiddle(answer *models.DuelAnswer) error {
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(answer).Error
+}
+
+func (r *duelRepository) FinishPlay(playID uint, finishedAt time.Time) error {
+	return database.DB.Model(&models.DuelPlay{}).
+		Where("id = ? AND finished_at IS NULL", playID).
+		Update("finished_at", finishedAt).Error
+}
+
+func (r *duelRepository) withDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Challenger").Preload("Opponent").
+		Preload("Riddles", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).Preload("Riddles.Riddle", includingDeleted).Preload("Riddles.Riddle.Category").
+		Preload("Riddles.Riddle.Media", mediaOrder).
+		Preload("Plays.Answers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		})
+}
\ No newline at end of file
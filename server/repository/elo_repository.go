@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+type EloRepository interface {
+	ApplyResult(userID uint, userDelta float64, riddleID uint, riddleDelta float64) error
+	FindUnsolvedNear(userID uint, rating float64, limit int) ([]models.Riddle, error) // closest ratings first
+	SeedRiddleRatings(ratings map[string]float64) error                               // difficulty -> rating, for unplayed riddles
+	RecalibrateDifficulty(easyBelow, hardFrom float64, minGames int) (int, error)     // sets rated_difficulty, never the editor's difficulty
+}
+
+type eloRepository struct{}
+
+func NewEloRepository() EloRepository {
+	return &eloRepository{}
+}
+
+func (r *eloRepository) ApplyResult(userID uint, userDelta float64, riddleID uint, riddleDelta float64) error {
+	// Deltas are added in place so concurrent results don't overwrite each other
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"rating":       gorm.Expr("rating + ?", userDelta),
+			"rating_games": gorm.Expr("rating_games + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Riddle{}).Where("id = ?", riddleID).Updates(map[string]interface{}{
+			"rating":       gorm.Expr("rating + ?", riddleDelta),
+			"rating_games": gorm.Expr("rating_games + 1"),
+		}).Error
+	})
+}
+
+func (r *eloRepository) FindUnsolvedNear(userID uint, rating float64, limit int) ([]models.Riddle, error) {
+	var riddles []models.Riddle
+	query := database.DB.Scopes(published, withMedia).Preload("Category")
+	if userID != 0 {
+		query = query.Where("id NOT IN (?)", database.DB.Model(&models.UserRiddleProgress{}).
+			Select("riddle_id").
+			Where("user_id = ? AND (solved = ? OR revealed = ?)", userID, true, true))
+	}
+	err := query.Order(gorm.Expr("ABS(rating - ?), id", rating)).Limit(limit).Find(&riddles).Error
+	return riddles, err
+}
+
+func (r *eloRepository) SeedRiddleRatings(ratings map[string]float64) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		for difficulty, rating := range ratings {
+			err := tx.Model(&models.Riddle{}).
+				Where("rating_games = 0 AND difficulty = ? AND rating <> ?", difficulty, rating).
+				Update("rating", rating).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *eloRepository) RecalibrateDifficulty(easyBelow, hardFrom float64, minGames int) (int, error) {
+	bucket := gorm.Expr("CASE WHEN rating < ? THEN 'easy' WHEN rating < ? THEN 'medium' ELSE 'hard' END", easyBelow, hardFrom)
+	result := database.DB.Model(&models.Riddle{}).
+		Where("rating_games >= ? AND rated_difficulty <> (?)", minGames, bucket).
+		Update("rated_difficulty", bucket)
+	return int(result.RowsAffected), result.Error
+}
\ No newline at end of file
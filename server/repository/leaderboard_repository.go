@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeaderboardRow is a ranked user on a leaderboard
+type LeaderboardRow struct {
+	Rank            int    `json:"rank"`
+	Position        int    `json:"-"` // unique ordering used to cut the top N
+	UserID          uint   `json:"user_id"`
+	Username        string `json:"username"`
+	Score           int    `json:"score"`
+	Solved          int    `json:"solved,omitempty"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+}
+
+type LeaderboardRepository interface {
+	AddScore(board string, userID uint, points int) error
+	AddDayScore(day time.Time, userID uint, points int) error
+	AddDailySpeedSolve(day time.Time, userID uint, startedAt, solvedAt time.Time) error
+	TopByBoard(board string, limit int, userID uint) ([]LeaderboardRow, error)
+	TopSince(since time.Time, limit int, userID uint) ([]LeaderboardRow, error)
+	TopDailySpeed(day time.Time, limit int, userID uint) ([]LeaderboardRow, error)
+	Rebuild(weights map[string]int, defaultWeight int, timezone string) error
+}
+
+type leaderboardRepository struct{}
+
+func NewLeaderboardRepository() LeaderboardRepository {
+	return &leaderboardRepository{}
+}
+
+func (r *leaderboardRepository) AddScore(board string, userID uint, points int) error {
+	score := &models.LeaderboardScore{Board: board, UserID: userID, Score: points}
+	return database.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "board"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"score":      gorm.Expr("leaderboard_scores.score + EXCLUDED.score"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(score).Error
+}
+
+func (r *leaderboardRepository) AddDayScore(day time.Time, userID uint, points int) error {
+	bucket := &models.LeaderboardDay{Day: day, UserID: userID, Score: points}
+	return database.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"score": gorm.Expr("leaderboard_days.score + EXCLUDED.score"),
+		}),
+	}).Create(bucket).Error
+}
+
+func (r *leaderboardRepository) AddDailySpeedSolve(day time.Time, userID uint, startedAt, solvedAt time.Time) error {
+	return database.DB.Exec(`
+		INSERT INTO daily_speed_scores (day, user_id, solved, started_at, finished_at, duration_seconds)
+		VALUES (?, ?, 1, ?, ?, GREATEST(EXTRACT(EPOCH FROM ?::timestamptz - ?::timestamptz), 0)::int)
+		ON CONFLICT (day, user_id) DO UPDATE SET
+			solved = daily_speed_scores.solved + 1,
+			started_at = LEAST(daily_speed_scores.started_at, EXCLUDED.started_at),
+			finished_at = GREATEST(daily_speed_scores.finished_at, EXCLUDED.finished_at),
+			duration_seconds = EXTRACT(EPOCH FROM GREATEST(daily_speed_scores.finished_at, EXCLUDED.finished_at) - LEAST(daily_speed_scores.started_at, EXCLUDED.started_at))::int`,
+		day.Format("2006-01-02"), userID, startedAt, solvedAt, solvedAt, startedAt).Error
+}
+
+func (r *leaderboardRepository) TopByBoard(board string, limit int, userID uint) ([]LeaderboardRow, error) {
+	return rankedRows(
+		"SELECT user_id, score, 0 AS solved, 0 AS duration_seconds FROM leaderboard_scores WHERE board = ? AND score > 0",
+		[]interface{}{board}, "score DESC", limit, userID)
+}
+
+func (r *leaderboardRepository) TopSince(since time.Time, limit int, userID uint) ([]LeaderboardRow, error) {
+	return rankedRows(
+		"SELECT user_id, SUM(score) AS score, 0 AS solved, 0 AS duration_seconds FROM leaderboard_days WHERE day >= ? GROUP BY user_id",
+		[]interface{}{since.Format("2006-01-02")}, "score DESC", limit, userID)
+}
+
+func (r *leaderboardRepository) TopDailySpeed(day time.Time, limit int, userID uint) ([]LeaderboardRow, error) {
+	return rankedRows(
+		"SELECT user_id, solved AS score, solved, duration_seconds FROM daily_speed_scores WHERE day = ?",
+		[]interface{}{day.Format("2006-01-02")}, "solved DESC, duration_seconds ASC", limit, userID)
+}
+
+// rankedRows ranks the rows of the totals query and returns the top N plus the given user's row
+func rankedRows(totals string, args []interface{}, order string, limit int, userID uint) ([]LeaderboardRow, error) {
+	query := `
+		WITH totals AS (` + totals + `),
+		ranked AS (
+			SELECT totals.*,
+				RANK() OVER (ORDER BY ` + order + `) AS rank,
+				ROW_NUMBER() OVER (ORDER BY ` + order + `, user_id) AS position
+			FROM totals
+		)
+		SELECT ranked.*, users.username
+		FROM ranked
+		JOIN users ON users.id = ranked.user_id
+		WHERE ranked.position <= ? OR ranked.user_id = ?
+		ORDER BY ranked.position`
+
+	var rows []LeaderboardRow
+	err := database.DB.Raw(query, append(args, limit, userID)...).Scan(&rows).Error
+	return rows, err
+}
+
+// Rebuild recomputes all leaderboard tables from solve progress. It scans the
+// whole progress table, so it's meant for backfills rather than requests.
+func (r *leaderboardRepository) Rebuild(weights map[string]int, defaultWeight int, timezone string) error {
+	weight := weightExpression(weights, defaultWeight)
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		statements := []struct {
+			sql  string
+			args []interface{}
+		}{
+			{sql: "DELETE FROM leaderboard_scores"},
+			{sql: "DELETE FROM leaderboard_days"},
+			{sql: "DELETE FROM daily_speed_scores"},
+			{sql: `
+				INSERT INTO leaderboard_scores (board, user_id, score, updated_at)
+				SELECT 'all', p.user_id, SUM(` + weight + `), NOW()
+				FROM user_riddle_progresses p JOIN riddles r ON r.id = p.riddle_id
+				WHERE p.solved
+				GROUP BY p.user_id`},
+			{sql: `
+				INSERT INTO leaderboard_scores (board, user_id, score, updated_at)
+				SELECT 'category:' || r.category_id, p.user_id, SUM(` + weight + `), NOW()
+				FROM user_riddle_progresses p JOIN riddles r ON r.id = p.riddle_id
+				WHERE p.solved
+				GROUP BY r.category_id, p.user_id`},
+			{sql: `
+				INSERT INTO leaderboard_days (day, user_id, score)
+				SELECT (p.solved_at AT TIME ZONE ?)::date, p.user_id, SUM(` + weight + `)
+				FROM user_riddle_progresses p JOIN riddles r ON r.id = p.riddle_id
+				WHERE p.solved
+				GROUP BY 1, p.user_id`, args: []interface{}{timezone}},
+			{sql: `
+				INSERT INTO daily_speed_scores (day, user_id, solved, started_at, finished_at, duration_seconds)
+				SELECT d.featured_date, p.user_id, COUNT(*), MIN(COALESCE(p.started_at, p.created_at)), MAX(p.solved_at),
+					GREATEST(EXTRACT(EPOCH FROM MAX(p.solved_at) - MIN(COALESCE(p.started_at, p.created_at))), 0)::int
+				FROM user_riddle_progresses p
+				JOIN daily_riddles d ON d.riddle_id = p.riddle_id AND d.featured_date = (p.solved_at AT TIME ZONE ?)::date
+				WHERE p.solved
+				GROUP BY d.featured_date, p.user_id`, args: []interface{}{timezone}},
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement.sql, statement.args...).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// weightExpression builds a SQL CASE mapping r.difficulty to its weight
+func weightExpression(weights map[string]int, defaultWeight int) string {
+	difficulties := make([]string, 0, len(weights))
+	for difficulty := range weights {
+		difficulties = append(difficulties, difficulty)
+	}
+	sort.Strings(difficulties)
+
+	var b strings.Builder
+	b.WriteString("CASE r.difficulty")
+	for _, difficulty := range difficulties {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", strings.ReplaceAll(difficulty, "'", "''"), weights[difficulty])
+	}
+	fmt.Fprintf(&b, " ELSE %d END", defaultWeight)
+	return b.String()
+}
\ No newline at end of file
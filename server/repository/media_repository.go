@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+)
+
+type MediaRepository interface {
+	Create(media *models.RiddleMedia) error
+	FindByID(id uint) (*models.RiddleMedia, error)
+	FindByRiddle(riddleID uint) ([]models.RiddleMedia, error) // in display order
+	CountByRiddle(riddleID uint) (int, error)
+	Delete(id uint) error
+}
+
+type mediaRepository struct{}
+
+func NewMediaRepository() MediaRepository {
+	return &mediaRepository{}
+}
+
+func (r *mediaRepository) Create(media *models.RiddleMedia) error {
+	return database.DB.Omit("Riddle", "UploadedBy").Create(media).Error
+}
+
+func (r *mediaRepository) FindByID(id uint) (*models.RiddleMedia, error) {
+	var media models.RiddleMedia
+	err := database.DB.First(&media, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *mediaRepository) FindByRiddle(riddleID uint) ([]models.RiddleMedia, error) {
+	var media []models.RiddleMedia
+	err := database.DB.Where("riddle_id = ?", riddleID).Order("position, id").Find(&media).Error
+	return media, err
+}
+
+func (r *mediaRepository) CountByRiddle(riddleID uint) (int, error) {
+	var count int64
+	err := database.DB.Model(&models.RiddleMedia{}).Where("riddle_id = ?", riddleID).Count(&count).Error
+	return int(count), err
+}
+
+func (r *mediaRepository) Delete(id uint) error {
+	return database.DB.Delete(&models.RiddleMedia{}, id).Error
+}
\ No newline at end of file
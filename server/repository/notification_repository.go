@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateBatch(notifications []models.Notification) error
+	FindByUser(userID uint, limit int) ([]models.Notification, error) // newest first
+	CountUnread(userID uint) (int, error)
+	MarkRead(userID, notificationID uint, readAt time.Time) (bool, error)
+	MarkAllRead(userID uint, readAt time.Time) error
+}
+
+type notificationRepository struct{}
+
+func NewNotificationRepository() NotificationRepository {
+	return &notificationRepository{}
+}
+
+func (r *notificationRepository) CreateBatch(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return database.DB.Omit("User").Create(&notifications).Error
+}
+
+func (r *notificationRepository) FindByUser(userID uint, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := database.DB.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) CountUnread(userID uint) (int, error) {
+	var count int64
+	err := database.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return int(count), err
+}
+
+// MarkRead returns false when the notification doesn't belong to the user
+func (r *notificationRepository) MarkRead(userID, notificationID uint, readAt time.Time) (bool, error) {
+	var notification models.Notification
+	err := database.DB.Where("id = ? AND user_id = ?", notificationID, userID).First(&notification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if notification.ReadAt != nil {
+		return true, nil
+	}
+	err = database.DB.Model(&notification).Update("read_at", readAt).Error
+	return err == nil, err
+}
+
+func (r *notificationRepository) MarkAllRead(userID uint, readAt time.Time) error {
+	return database.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Update("read_at", readAt).Error
+}
\ No newline at end of file
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+type PlacementRepository interface {
+	Create(test *models.PlacementTest) error
+	FindLatest(userID uint) (*models.PlacementTest, error)
+	FindCandidate(userID uint, difficulty string, excludeRiddles, excludeCategories []uint) (*models.Riddle, error) // random match, filters skipped when empty
+	AddStep(step *models.PlacementStep) error
+	AnswerStep(stepID uint, answer string, correct bool, answeredAt time.Time) (bool, error) // false when already answered
+	Update(test *models.PlacementTest) error
+}
+
+type placementRepository struct{}
+
+func NewPlacementRepository() PlacementRepository {
+	return &placementRepository{}
+}
+
+func (r *placementRepository) Create(test *models.PlacementTest) error {
+	return database.DB.Create(test).Error
+}
+
+func (r *placementRepository) FindLatest(userID uint) (*models.PlacementTest, error) {
+	var test models.PlacementTest
+	err := database.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Preload("Steps.Riddle", includingDeleted).Preload("Steps.Riddle.Category").Preload("Steps.Riddle.Media", mediaOrder).
+		Where("user_id = ?", userID).
+		Order("started_at DESC").
+		First(&test).Error
+	if err != nil {
+		return nil, err
+	}
+	return &test, nil
+}
+
+func (r *placementRepository) FindCandidate(userID uint, difficulty string, excludeRiddles, excludeCategories []uint) (*models.Riddle, error) {
+	var riddle models.Riddle
+	query := database.DB.Scopes(published, withMedia).Preload("Category").
+		Where("id NOT IN (?)", database.DB.Model(&models.UserRiddleProgress{}).
+			Select("riddle_id").
+			Where("user_id = ? AND (solved = ? OR revealed = ?)", userID, true, true))
+	if difficulty != "" {
+		query = query.Where("difficulty = ?", difficulty)
+	}
+	if len(excludeRiddles) > 0 {
+		query = query.Where("id NOT IN ?", excludeRiddles)
+	}
+	if len(excludeCategories) > 0 {
+		query = query.Where("category_id NOT IN ?", excludeCategories)
+	}
+	err := query.Order("RANDOM()").First(&riddle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &riddle, nil
+}
+
+func (r *placementRepository) AddStep(step *models.PlacementStep) error {
+	return database.DB.Omit("Riddle").Create(step).Error
+}
+
+func (r *placementRepository) AnswerStep(stepID uint, answer string, correct bool, answeredAt time.Time) (bool, error) {
+	result := database.DB.Model(&models.PlacementStep{}).
+		Where("id = ? AND answered_at IS NULL", stepID).
+		Updates(map[string]interface{}{
+			"answer":      answer,
+			"correct":     correct,
+			"answered_at": answeredAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *placementRepository) Update(test *models.PlacementTest) error {
+	return database.DB.Model(test).Updates(map[string]interface{}{
+		"estimated_rating": test.EstimatedRating,
+		"finished_at":      test.FinishedAt,
+	}).Error
+}
\ No newline at end of file
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+)
+
+// PointsRepository only appends to the ledger; entries are never updated or deleted
+type PointsRepository interface {
+	Create(entry *models.PointsEntry) error
+	FindByUserID(userID uint) ([]models.PointsEntry, error)
+	GetUserTotal(userID uint) (int, error)
+	GetUserRiddleTotals(userID uint) (map[uint]int, error) // riddle ID -> points
+}
+
+type pointsRepository struct{}
+
+func NewPointsRepository() PointsRepository {
+	return &pointsRepository{}
+}
+
+func (r *pointsRepository) Create(entry *models.PointsEntry) error {
+	return database.DB.Create(entry).Error
+}
+
+func (r *pointsRepository) FindByUserID(userID uint) ([]models.PointsEntry, error) {
+	var entries []models.PointsEntry
+	err := database.DB.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *pointsRepository) GetUserTotal(userID uint) (int, error) {
+	var total int64
+	err := database.DB.Model(&models.PointsEntry{}).Select("COALESCE(SUM(points), 0)").Where("user_id = ?", userID).Scan(&total).Error
+	return int(total), err
+}
+
+func (r *pointsRepository) GetUserRiddleTotals(userID uint) (map[uint]int, error) {
+	var rows []struct {
+		RiddleID uint
+		Total    int
+	}
+	err := database.DB.Model(&models.PointsEntry{}).
+		Select("riddle_id, SUM(points) AS total").
+		Where("user_id = ? AND riddle_id IS NOT NULL", userID).
+		Group("riddle_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		totals[row.RiddleID] = row.Total
+	}
+	return totals, nil
+}
\ No newline at end of file
@@ -45,8 +45,10 @@ func (r *progressRepository) Update(progress *models.UserRiddleProgress) error {
 func (r *progressRepository) GetUserStats(userID uint) (int, int, error) {
 	var total, solved int64
 	
-	// Get total riddles for user
-	err := database.DB.Model(&models.UserRiddleProgress{}).Where("user_id = ?", userID).Count(&total).Error
+	// Get total riddles for user; riddles only opened don't count as tried
+	err := database.DB.Model(&models.UserRiddleProgress{}).
+		Where("user_id = ? AND (attempts > 0 OR hints_used > 0 OR solved OR revealed)", userID).
+		Count(&total).Error
 	if err != nil {
 		return 0, 0, err
 	}
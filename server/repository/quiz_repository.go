@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+type QuizRepository interface {
+	Create(session *models.QuizSession) error
+	FindByID(id uint) (*models.QuizSession, error)
+	AnswerQuestion(questionID uint, answer string, correct bool, answeredAt time.Time) (bool, error) // false when already answered
+	Finish(session *models.QuizSession) error
+}
+
+type quizRepository struct{}
+
+func NewQuizRepository() QuizRepository {
+	return &quizRepository{}
+}
+
+func (r *quizRepository) Create(session *models.QuizSession) error {
+	return database.DB.Create(session).Error
+}
+
+func (r *quizRepository) FindByID(id uint) (*models.QuizSession, error) {
+	var session models.QuizSession
+	err := database.DB.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Preload("Questions.Riddle", includingDeleted).Preload("Questions.Riddle.Category").Preload("Questions.Riddle.Media", mediaOrder).First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *quizRepository) AnswerQuestion(questionID uint, answer string, correct bool, answeredAt time.Time) (bool, error) {
+	// The answered_at guard makes concurrent submissions for one question count once
+	result := database.DB.Model(&models.QuizQuestion{}).
+		Where("id = ? AND answered_at IS NULL", questionID).
+		Updates(map[string]interface{}{
+			"answer":      answer,
+			"correct":     correct,
+			"answered_at": answeredAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *quizRepository) Finish(session *models.QuizSession) error {
+	return database.DB.Model(session).
+		Where("finished_at IS NULL").
+		Updates(map[string]interface{}{
+			"finished_at": session.FinishedAt,
+			"score":       session.Score,
+		}).Error
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+// RecommendationSignal is a user's aggregated interest in a riddle: likes and
+// favorites count 1, solves 0.5, dislikes -1 and reveals 0
+type RecommendationSignal struct {
+	UserID   uint
+	RiddleID uint
+	Weight   float64
+}
+
+type RecommendationRepository interface {
+	LoadSignals() ([]RecommendationSignal, error)
+	Replace(userID uint, recommendations []models.Recommendation) error
+	FindForUser(listUserID, viewerID uint, excludeIDs []uint, limit int) ([]models.Recommendation, error) // skips riddles the viewer solved
+}
+
+type recommendationRepository struct{}
+
+func NewRecommendationRepository() RecommendationRepository {
+	return &recommendationRepository{}
+}
+
+func (r *recommendationRepository) LoadSignals() ([]RecommendationSignal, error) {
+	var signals []RecommendationSignal
+	err := database.DB.Raw(`
+		SELECT user_id, riddle_id, SUM(weight) AS weight
+		FROM (
+			SELECT user_id, riddle_id, rating::float AS weight FROM riddle_ratings
+			UNION ALL
+			SELECT user_id, riddle_id, 1 FROM favorites
+			UNION ALL
+			SELECT user_id, riddle_id, CASE WHEN solved THEN 0.5 ELSE 0 END
+			FROM user_riddle_progresses
+			WHERE solved OR revealed
+		) AS signals
+		GROUP BY user_id, riddle_id`).Scan(&signals).Error
+	return signals, err
+}
+
+func (r *recommendationRepository) Replace(userID uint, recommendations []models.Recommendation) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Recommendation{}).Error; err != nil {
+			return err
+		}
+		if len(recommendations) == 0 {
+			return nil
+		}
+		return tx.Omit("Riddle").Create(&recommendations).Error
+	})
+}
+
+func (r *recommendationRepository) FindForUser(listUserID, viewerID uint, excludeIDs []uint, limit int) ([]models.Recommendation, error) {
+	var recommendations []models.Recommendation
+	query := database.DB.Preload("Riddle.Category").Preload("Riddle.Media", mediaOrder).
+		Where("user_id = ?", listUserID).
+		Where("riddle_id IN (?)", database.DB.Model(&models.Riddle{}).Scopes(published).Select("id"))
+	if viewerID != 0 {
+		query = query.Where("riddle_id NOT IN (?)", database.DB.Model(&models.UserRiddleProgress{}).
+			Select("riddle_id").
+			Where("user_id = ? AND solved = ?", viewerID, true))
+	}
+	if len(excludeIDs) > 0 {
+		query = query.Where("riddle_id NOT IN ?", excludeIDs)
+	}
+	err := query.Order("score DESC, riddle_id").Limit(limit).Find(&recommendations).Error
+	return recommendations, err
+}
\ No newline at end of file
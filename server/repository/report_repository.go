@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"errors"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	Create(report *models.RiddleReport) error
+	HasOpen(riddleID, reporterID uint, reason string) (bool, error)
+	FindOpen() ([]models.RiddleReport, error) // oldest first
+	FindByReporter(reporterID uint) ([]models.RiddleReport, error)
+	Resolve(resolution *models.ReportResolution) ([]models.RiddleReport, error) // returns the reports it closed
+}
+
+type reportRepository struct{}
+
+func NewReportRepository() ReportRepository {
+	return &reportRepository{}
+}
+
+func (r *reportRepository) Create(report *models.RiddleReport) error {
+	return database.DB.Omit("Riddle", "Reporter").Create(report).Error
+}
+
+func (r *reportRepository) HasOpen(riddleID, reporterID uint, reason string) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.RiddleReport{}).
+		Where("riddle_id = ? AND reporter_id = ? AND reason = ? AND status = ?", riddleID, reporterID, reason, models.ReportOpen).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *reportRepository) FindOpen() ([]models.RiddleReport, error) {
+	var reports []models.RiddleReport
+	err := database.DB.Preload("Riddle").Preload("Reporter").
+		Where("status = ?", models.ReportOpen).
+		Order("created_at, id").
+		Find(&reports).Error
+	return reports, err
+}
+
+func (r *reportRepository) FindByReporter(reporterID uint) ([]models.RiddleReport, error) {
+	var reports []models.RiddleReport
+	err := database.DB.Preload("Resolution").Where("reporter_id = ?", reporterID).Order("created_at DESC").Find(&reports).Error
+	return reports, err
+}
+
+// Resolve records the resolution and closes the riddle's open reports in one
+// transaction. No reports means another moderator got there first and nothing
+// is written.
+func (r *reportRepository) Resolve(resolution *models.ReportResolution) ([]models.RiddleReport, error) {
+	var reports []models.RiddleReport
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("riddle_id = ? AND status = ?", resolution.RiddleID, models.ReportOpen).Find(&reports).Error; err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(resolution).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uint, len(reports))
+		for i, report := range reports {
+			ids[i] = report.ID
+		}
+		result := tx.Model(&models.RiddleReport{}).
+			Where("id IN ? AND status = ?", ids, models.ReportOpen).
+			Updates(map[string]interface{}{
+				"status":        resolution.Outcome,
+				"resolution_id": resolution.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if int(result.RowsAffected) != len(reports) {
+			// A concurrent resolution closed some of them; let it win
+			reports = nil
+			return errAlreadyReviewed
+		}
+		return nil
+	})
+	if errors.Is(err, errAlreadyReviewed) {
+		return nil, nil
+	}
+	return reports, err
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+)
+
+type ReviewRepository interface {
+	FindByUserAndRiddle(userID, riddleID uint) (*models.ReviewItem, error)
+	FindPublishedByUserAndRiddle(userID, riddleID uint) (*models.ReviewItem, error) // not found unless the riddle is published
+	FindDue(userID uint, date time.Time, limit int) ([]models.ReviewItem, error)
+	CountDue(userID uint, date time.Time) (int, error)
+	Save(item *models.ReviewItem) error
+}
+
+type reviewRepository struct{}
+
+func NewReviewRepository() ReviewRepository {
+	return &reviewRepository{}
+}
+
+func (r *reviewRepository) FindByUserAndRiddle(userID, riddleID uint) (*models.ReviewItem, error) {
+	var item models.ReviewItem
+	err := database.DB.Preload("Riddle", includingDeleted).Where("user_id = ? AND riddle_id = ?", userID, riddleID).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *reviewRepository) FindPublishedByUserAndRiddle(userID, riddleID uint) (*models.ReviewItem, error) {
+	var item models.ReviewItem
+	err := database.DB.Preload("Riddle", includingDeleted).
+		Where("user_id = ? AND riddle_id = ?", userID, riddleID).
+		Where("riddle_id IN (?)", database.DB.Model(&models.Riddle{}).Scopes(published).Select("id")).
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *reviewRepository) FindDue(userID uint, date time.Time, limit int) ([]models.ReviewItem, error) {
+	var items []models.ReviewItem
+	err := database.DB.Preload("Riddle", includingDeleted).Preload("Riddle.Category").Preload("Riddle.Media", mediaOrder).
+		Where("user_id = ? AND due_date <= ?", userID, date.Format("2006-01-02")).
+		Where("riddle_id IN (?)", database.DB.Model(&models.Riddle{}).Scopes(published).Select("id")).
+		Order("due_date, id").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+func (r *reviewRepository) CountDue(userID uint, date time.Time) (int, error) {
+	var count int64
+	err := database.DB.Model(&models.ReviewItem{}).
+		Where("user_id = ? AND due_date <= ?", userID, date.Format("2006-01-02")).
+		Where("riddle_id IN (?)", database.DB.Model(&models.Riddle{}).Scopes(published).Select("id")).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *reviewRepository) Save(item *models.ReviewItem) error {
+	return database.DB.Omit("User", "Riddle").Save(item).Error
+}
\ No newline at end of file
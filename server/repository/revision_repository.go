@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+)
+
+type RevisionRepository interface {
+	FindByRiddle(riddleID uint) ([]models.RiddleRevision, error) // newest first
+	FindByVersion(riddleID uint, version int) (*models.RiddleRevision, error)
+	Exists(riddleID uint) (bool, error)
+}
+
+type revisionRepository struct{}
+
+func NewRevisionRepository() RevisionRepository {
+	return &revisionRepository{}
+}
+
+func (r *revisionRepository) FindByRiddle(riddleID uint) ([]models.RiddleRevision, error) {
+	var revisions []models.RiddleRevision
+	err := database.DB.Preload("Author").Where("riddle_id = ?", riddleID).Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *revisionRepository) FindByVersion(riddleID uint, version int) (*models.RiddleRevision, error) {
+	var revision models.RiddleRevision
+	err := database.DB.Where("riddle_id = ? AND version = ?", riddleID, version).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *revisionRepository) Exists(riddleID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.RiddleRevision{}).Where("riddle_id = ?", riddleID).Count(&count).Error
+	return count > 0, err
+}
\ No newline at end of file
@@ -1,17 +1,47 @@
 package repository
 
 import (
+	"math/rand"
+	"time"
 	"riddles-server/database"
 	"riddles-server/models"
+
+	"gorm.io/gorm"
 )
 
+// RandomRiddleFilter narrows FindRandomOne; zero values are ignored
+type RandomRiddleFilter struct {
+	CategoryID      uint
+	Difficulty      string
+	ExcludeSolvedBy uint // user whose solved riddles are skipped
+	ExcludeIDs      []uint
+}
+
+// RiddleFilter narrows FindFiltered; zero values are ignored
+type RiddleFilter struct {
+	Query          string   // matched against title and description
+	Tags           []string // tag keys; a riddle must carry all of them
+	CategoryIDs    []uint   // a riddle must be in one of them
+	NearDifficulty string   // orders this difficulty first, then the closest ones
+}
+
 type RiddleRepository interface {
 	FindAll() ([]models.Riddle, error)
-	FindByID(id uint) (*models.Riddle, error)
+	FindByID(id uint) (*models.Riddle, error)          // any status, deleted ones included
+	FindPublishedByID(id uint) (*models.Riddle, error) // with media, like FindFiltered and FindRandomOne
 	FindByCategory(categoryID uint) ([]models.Riddle, error)
 	FindByDifficulty(difficulty string) ([]models.Riddle, error)
 	FindByCategoryAndDifficulty(categoryID uint, difficulty string) ([]models.Riddle, error)
 	Search(query string) ([]models.Riddle, error)
+	FindRandom(categoryID uint, difficulty string, limit int) ([]models.Riddle, error) // filters are skipped when zero
+	FindFiltered(filter RiddleFilter) ([]models.Riddle, error)
+	FindRandomOne(filter RandomRiddleFilter) (*models.Riddle, error)
+	CreateWithRevision(riddle *models.Riddle, revision *models.RiddleRevision) error
+	UpdateWithRevisions(riddle *models.Riddle, expectedVersion int, revisions ...models.RiddleRevision) (bool, error) // false on a version conflict
+	CreateBatch(riddles []models.Riddle) error                                                                        // all or nothing, tags must already exist
+	FindForEditors(status string) ([]models.Riddle, error)                                                            // any status when empty, newest first
+	FindDueScheduled(now time.Time) ([]models.Riddle, error)
+	SoftDelete(id uint) error
 }
 
 type riddleRepository struct{}
@@ -20,38 +50,224 @@ func NewRiddleRepository() RiddleRepository {
 	return &riddleRepository{}
 }
 
+// published limits a query to the riddles players can see. The deleted_at
+// check is there for queries that join riddles rather than select them.
+func published(db *gorm.DB) *gorm.DB {
+	return db.Where("riddles.status = ? AND riddles.deleted_at IS NULL", models.RiddlePublished)
+}
+
+// withMedia loads the riddles' attachments in display order
+func withMedia(db *gorm.DB) *gorm.DB {
+	return db.Preload("Media", mediaOrder)
+}
+
+// includingDeleted lets preloads reach riddles deleted after they were
+// handed out, so games, tests and reviews in progress keep working
+func includingDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// mediaOrder sorts attachments for display; nested preloads like
+// Preload("Riddle.Media", mediaOrder) use it directly
+func mediaOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
 func (r *riddleRepository) FindAll() ([]models.Riddle, error) {
 	var riddles []models.Riddle
-	err := database.DB.Preload("Category").Find(&riddles).Error
+	err := database.DB.Scopes(published).Preload("Category").Preload("Tags").Find(&riddles).Error
 	return riddles, err
 }
 
 func (r *riddleRepository) FindByID(id uint) (*models.Riddle, error) {
 	var riddle models.Riddle
-	err := database.DB.Preload("Category").First(&riddle, id).Error
+	err := database.DB.Unscoped().Preload("Category").Preload("Tags").First(&riddle, id).Error
+	return &riddle, err
+}
+
+func (r *riddleRepository) FindPublishedByID(id uint) (*models.Riddle, error) {
+	var riddle models.Riddle
+	err := database.DB.Scopes(published, withMedia).Preload("Category").Preload("Tags").First(&riddle, id).Error
 	return &riddle, err
 }
 
 func (r *riddleRepository) FindByCategory(categoryID uint) ([]models.Riddle, error) {
 	var riddles []models.Riddle
-	err := database.DB.Preload("Category").Where("category_id = ?", categoryID).Find(&riddles).Error
+	err := database.DB.Scopes(published).Preload("Category").Where("category_id = ?", categoryID).Find(&riddles).Error
 	return riddles, err
 }
 
 func (r *riddleRepository) FindByDifficulty(difficulty string) ([]models.Riddle, error) {
 	var riddles []models.Riddle
-	err := database.DB.Preload("Category").Where("difficulty = ?", difficulty).Find(&riddles).Error
+	err := database.DB.Scopes(published).Preload("Category").Where("difficulty = ?", difficulty).Find(&riddles).Error
 	return riddles, err
 }
 
 func (r *riddleRepository) FindByCategoryAndDifficulty(categoryID uint, difficulty string) ([]models.Riddle, error) {
 	var riddles []models.Riddle
-	err := database.DB.Preload("Category").Where("category_id = ? AND difficulty = ?", categoryID, difficulty).Find(&riddles).Error
+	err := database.DB.Scopes(published).Preload("Category").Where("category_id = ? AND difficulty = ?", categoryID, difficulty).Find(&riddles).Error
 	return riddles, err
 }
 
 func (r *riddleRepository) Search(query string) ([]models.Riddle, error) {
 	var riddles []models.Riddle
-	err := database.DB.Preload("Category").Where("title ILIKE ? OR description ILIKE ?", "%"+query+"%", "%"+query+"%").Find(&riddles).Error
+	err := database.DB.Scopes(published).Preload("Category").Preload("Tags").Where("title ILIKE ? OR description ILIKE ?", "%"+query+"%", "%"+query+"%").Find(&riddles).Error
+	return riddles, err
+}
+
+func (r *riddleRepository) FindRandom(categoryID uint, difficulty string, limit int) ([]models.Riddle, error) {
+	var riddles []models.Riddle
+	query := database.DB.Scopes(published, withMedia).Preload("Category")
+	if categoryID != 0 {
+		query = query.Where("category_id = ?", categoryID)
+	}
+	if difficulty != "" {
+		query = query.Where("difficulty = ?", difficulty)
+	}
+	err := query.Order("RANDOM()").Limit(limit).Find(&riddles).Error
+	return riddles, err
+}
+
+// FindRandomOne counts the matches and fetches one at a random offset, which
+// spares the database from sorting the whole table
+func (r *riddleRepository) FindRandomOne(filter RandomRiddleFilter) (*models.Riddle, error) {
+	query := database.DB.Model(&models.Riddle{}).Scopes(published)
+	if filter.CategoryID != 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	if filter.Difficulty != "" {
+		query = query.Where("difficulty = ?", filter.Difficulty)
+	}
+	if filter.ExcludeSolvedBy != 0 {
+		query = query.Where("id NOT IN (?)", database.DB.Model(&models.UserRiddleProgress{}).
+			Select("riddle_id").
+			Where("user_id = ? AND solved = ?", filter.ExcludeSolvedBy, true))
+	}
+	if len(filter.ExcludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", filter.ExcludeIDs)
+	}
+
+	var count int64
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var riddle models.Riddle
+	err := query.Scopes(withMedia).Preload("Category").Preload("Tags").Order("id").Offset(rand.Intn(int(count))).Limit(1).Take(&riddle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &riddle, nil
+}
+
+// difficultyLevel orders difficulties from easiest to hardest
+const difficultyLevel = "CASE difficulty WHEN 'easy' THEN 0 WHEN 'medium' THEN 1 ELSE 2 END"
+
+var difficultyLevels = map[string]int{"easy": 0, "medium": 1, "hard": 2}
+
+func (r *riddleRepository) FindFiltered(filter RiddleFilter) ([]models.Riddle, error) {
+	query := database.DB.Scopes(published, withMedia).Preload("Category").Preload("Tags")
+	if filter.Query != "" {
+		pattern := "%" + filter.Query + "%"
+		query = query.Where("(title ILIKE ? OR description ILIKE ?)", pattern, pattern)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if len(filter.Tags) > 0 {
+		query = query.Where("id IN (?)", database.DB.Table("riddle_tags").
+			Select("riddle_tags.riddle_id").
+			Joins("JOIN tags ON tags.id = riddle_tags.tag_id").
+			Where("tags.key IN ?", filter.Tags).
+			Group("riddle_tags.riddle_id").
+			Having("COUNT(*) = ?", len(filter.Tags)))
+	}
+	if filter.NearDifficulty != "" {
+		query = query.Order(gorm.Expr("ABS("+difficultyLevel+" - ?), "+difficultyLevel+", id", difficultyLevels[filter.NearDifficulty]))
+	}
+
+	var riddles []models.Riddle
+	err := query.Find(&riddles).Error
 	return riddles, err
+}
+
+func (r *riddleRepository) CreateWithRevision(riddle *models.Riddle, revision *models.RiddleRevision) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category", "Tags").Create(riddle).Error; err != nil {
+			return err
+		}
+		revision.RiddleID = riddle.ID
+		revision.Version = riddle.Version
+		return tx.Omit("Riddle", "Author").Create(revision).Error
+	})
+}
+
+// UpdateWithRevisions saves the riddle's content and status only if nobody changed it
+// since expectedVersion, and records the revisions in the same transaction
+func (r *riddleRepository) UpdateWithRevisions(riddle *models.Riddle, expectedVersion int, revisions ...models.RiddleRevision) (bool, error) {
+	updated := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Riddle{}).
+			Where("id = ? AND version = ?", riddle.ID, expectedVersion).
+			Updates(map[string]interface{}{
+				"title":            riddle.Title,
+				"description":      riddle.Description,
+				"answer":           riddle.Answer,
+				"accepted_answers": riddle.AcceptedAnswers,
+				"explanation":      riddle.Explanation,
+				"category_id":      riddle.CategoryID,
+				"difficulty":       riddle.Difficulty,
+				"type":             riddle.Type,
+				"payload":          riddle.Payload,
+				"key":              riddle.Key,
+				"version":          riddle.Version,
+				"status":           riddle.Status,
+				"publish_at":       riddle.PublishAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if len(revisions) > 0 {
+			if err := tx.Omit("Riddle", "Author").Create(&revisions).Error; err != nil {
+				return err
+			}
+		}
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+func (r *riddleRepository) CreateBatch(riddles []models.Riddle) error {
+	if len(riddles) == 0 {
+		return nil
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Omit("Category", "Tags.*").Create(&riddles).Error
+	})
+}
+
+func (r *riddleRepository) FindForEditors(status string) ([]models.Riddle, error) {
+	var riddles []models.Riddle
+	query := database.DB.Preload("Category").Preload("Tags")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("updated_at DESC, id DESC").Find(&riddles).Error
+	return riddles, err
+}
+
+func (r *riddleRepository) FindDueScheduled(now time.Time) ([]models.Riddle, error) {
+	var riddles []models.Riddle
+	err := database.DB.Where("status = ? AND publish_at <= ?", models.RiddleScheduled, now).Order("publish_at").Find(&riddles).Error
+	return riddles, err
+}
+
+// SoftDelete hides the riddle everywhere but keeps the rows that point at it
+func (r *riddleRepository) SoftDelete(id uint) error {
+	return database.DB.Delete(&models.Riddle{}, id).Error
 }
\ No newline at end of file
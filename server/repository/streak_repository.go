@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+)
+
+type StreakRepository interface {
+	FindByUserID(userID uint) (*models.UserStreak, error)
+	Save(streak *models.UserStreak) error
+}
+
+type streakRepository struct{}
+
+func NewStreakRepository() StreakRepository {
+	return &streakRepository{}
+}
+
+func (r *streakRepository) FindByUserID(userID uint) (*models.UserStreak, error) {
+	var streak models.UserStreak
+	err := database.DB.Where("user_id = ?", userID).First(&streak).Error
+	if err != nil {
+		return nil, err
+	}
+	return &streak, nil
+}
+
+func (r *streakRepository) Save(streak *models.UserStreak) error {
+	return database.DB.Save(streak).Error
+}
\ No newline at end of file
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+)
+
+type SubmissionRepository interface {
+	Create(submission *models.RiddleSubmission) error
+	FindByID(id uint) (*models.RiddleSubmission, error)
+	FindByAuthor(authorID uint) ([]models.RiddleSubmission, error)
+	FindByStatus(status string) ([]models.RiddleSubmission, error) // oldest first
+	Approve(submission *models.RiddleSubmission, riddle *models.Riddle) (bool, error)
+	Reject(submission *models.RiddleSubmission) (bool, error)
+}
+
+type submissionRepository struct{}
+
+func NewSubmissionRepository() SubmissionRepository {
+	return &submissionRepository{}
+}
+
+func (r *submissionRepository) Create(submission *models.RiddleSubmission) error {
+	return database.DB.Create(submission).Error
+}
+
+func (r *submissionRepository) FindByID(id uint) (*models.RiddleSubmission, error) {
+	var submission models.RiddleSubmission
+	err := database.DB.Preload("Category").Preload("Author").First(&submission, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *submissionRepository) FindByAuthor(authorID uint) ([]models.RiddleSubmission, error) {
+	var submissions []models.RiddleSubmission
+	err := database.DB.Preload("Category").Where("author_id = ?", authorID).Order("created_at DESC").Find(&submissions).Error
+	return submissions, err
+}
+
+func (r *submissionRepository) FindByStatus(status string) ([]models.RiddleSubmission, error) {
+	var submissions []models.RiddleSubmission
+	err := database.DB.Preload("Category").Preload("Author").Where("status = ?", status).Order("created_at, id").Find(&submissions).Error
+	return submissions, err
+}
+
+// Approve publishes the riddle and marks the submission in one transaction;
+// false means another moderator already handled it
+func (r *submissionRepository) Approve(submission *models.RiddleSubmission, riddle *models.Riddle) (bool, error) {
+	approved := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category").Create(riddle).Error; err != nil {
+			return err
+		}
+		submission.RiddleID = &riddle.ID
+
+		result := tx.Model(&models.RiddleSubmission{}).
+			Where("id = ? AND status = ?", submission.ID, models.SubmissionPending).
+			Updates(reviewUpdates(submission))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Roll back the riddle created above
+			return errAlreadyReviewed
+		}
+		approved = true
+		return nil
+	})
+	if errors.Is(err, errAlreadyReviewed) {
+		return false, nil
+	}
+	return approved, err
+}
+
+func (r *submissionRepository) Reject(submission *models.RiddleSubmission) (bool, error) {
+	result := database.DB.Model(&models.RiddleSubmission{}).
+		Where("id = ? AND status = ?", submission.ID, models.SubmissionPending).
+		Updates(reviewUpdates(submission))
+	return result.RowsAffected > 0, result.Error
+}
+
+// errAlreadyReviewed aborts a review transaction when the submission or report was handled meanwhile
+var errAlreadyReviewed = errors.New("already reviewed")
+
+func reviewUpdates(submission *models.RiddleSubmission) map[string]interface{} {
+	return map[string]interface{}{
+		"title":            submission.Title,
+		"description":      submission.Description,
+		"answer":           submission.Answer,
+		"explanation":      submission.Explanation,
+		"category_id":      submission.CategoryID,
+		"difficulty":       submission.Difficulty,
+		"status":           submission.Status,
+		"moderator_id":     submission.ModeratorID,
+		"rejection_reason": submission.RejectionReason,
+		"reviewed_at":      submission.ReviewedAt,
+		"riddle_id":        submission.RiddleID,
+	}
+}
\ No newline at end of file
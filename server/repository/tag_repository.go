@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagCount is a tag with the number of riddles carrying it
+type TagCount struct {
+	models.Tag
+	Riddles int `json:"riddles"`
+}
+
+type TagRepository interface {
+	FindAllWithCounts() ([]TagCount, error) // most used first
+	FindByID(id uint) (*models.Tag, error)
+	FindByKey(key string) (*models.Tag, error)
+	FindOrCreate(tag *models.Tag) error // looks the tag up by key, creating it when missing
+	Update(tag *models.Tag) error
+	Delete(id uint) error
+	ReplaceRiddleTags(riddleID uint, tags []models.Tag) error
+}
+
+type tagRepository struct{}
+
+func NewTagRepository() TagRepository {
+	return &tagRepository{}
+}
+
+func (r *tagRepository) FindAllWithCounts() ([]TagCount, error) {
+	var tags []TagCount
+	err := database.DB.Model(&models.Tag{}).
+		Select("tags.*, COUNT(riddle_tags.riddle_id) AS riddles").
+		Joins("LEFT JOIN riddle_tags ON riddle_tags.tag_id = tags.id AND riddle_tags.riddle_id IN (?)",
+			database.DB.Model(&models.Riddle{}).Scopes(published).Select("id")).
+		Group("tags.id").
+		Order("riddles DESC, tags.key").
+		Scan(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) FindByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := database.DB.First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) FindByKey(key string) (*models.Tag, error) {
+	var tag models.Tag
+	err := database.DB.Where("key = ?", key).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindOrCreate tolerates concurrent creation of the same tag: the insert is
+// skipped on conflict and the existing row is read back
+func (r *tagRepository) FindOrCreate(tag *models.Tag) error {
+	err := database.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(tag).Error
+	if err != nil {
+		return err
+	}
+	return database.DB.Where("key = ?", tag.Key).First(tag).Error
+}
+
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return database.DB.Model(tag).Select("name", "key").Updates(tag).Error
+}
+
+func (r *tagRepository) Delete(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM riddle_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+func (r *tagRepository) ReplaceRiddleTags(riddleID uint, tags []models.Tag) error {
+	return database.DB.Model(&models.Riddle{ID: riddleID}).Association("Tags").Replace(tags)
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"riddles-server/database"
+	"riddles-server/models"
+
+	"gorm.io/gorm/clause"
+)
+
+type TeamRepository interface {
+	Create(team *models.Team) error
+	FindByID(id uint) (*models.Team, error)
+	FindByName(name string) (*models.Team, error)
+	FindByUserID(userID uint) ([]models.Team, error)
+	AddMember(member *models.TeamMember) (bool, error) // false when already a member
+	RemoveMember(teamID, userID uint) (bool, error)    // false when not a member
+	SetCaptain(teamID, userID uint) error
+	PlaysForRival(teamID, userID uint) (bool, error) // on another team of a game the team is in that isn't over
+}
+
+type teamRepository struct{}
+
+func NewTeamRepository() TeamRepository {
+	return &teamRepository{}
+}
+
+func (r *teamRepository) Create(team *models.Team) error {
+	return database.DB.Create(team).Error
+}
+
+func (r *teamRepository) FindByID(id uint) (*models.Team, error) {
+	var team models.Team
+	err := database.DB.Preload("Members.User").First(&team, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *teamRepository) FindByName(name string) (*models.Team, error) {
+	var team models.Team
+	err := database.DB.Where("LOWER(name) = LOWER(?)", name).First(&team).Error
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *teamRepository) FindByUserID(userID uint) ([]models.Team, error) {
+	var teams []models.Team
+	err := database.DB.Preload("Members.User").
+		Where("id IN (?)", database.DB.Model(&models.TeamMember{}).Select("team_id").Where("user_id = ?", userID)).
+		Order("name").
+		Find(&teams).Error
+	return teams, err
+}
+
+func (r *teamRepository) AddMember(member *models.TeamMember) (bool, error) {
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(member)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *teamRepository) RemoveMember(teamID, userID uint) (bool, error) {
+	result := database.DB.Where("team_id = ? AND user_id = ?", teamID, userID).Delete(&models.TeamMember{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *teamRepository) PlaysForRival(teamID, userID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.ChgkGameTeam{}).
+		Joins("JOIN chgk_games ON chgk_games.id = chgk_game_teams.game_id AND chgk_games.status <> ?", models.ChgkFinished).
+		Joins("JOIN chgk_game_teams rivals ON rivals.game_id = chgk_game_teams.game_id AND rivals.team_id <> chgk_game_teams.team_id").
+		Joins("JOIN team_members ON team_members.team_id = rivals.team_id AND team_members.user_id = ?", userID).
+		Where("chgk_game_teams.team_id = ?", teamID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *teamRepository) SetCaptain(teamID, userID uint) error {
+	return database.DB.Model(&models.Team{}).Where("id = ?", teamID).Update("captain_id", userID).Error
+}
\ No newline at end of file
@@ -3,12 +3,17 @@ package repository
 import (
 	"riddles-server/database"
 	"riddles-server/models"
+
+	"gorm.io/gorm"
 )
 
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id uint) (*models.User, error)
+	FindAllIDs() ([]uint, error)
+	SavePlacement(user *models.User) error // also seeds the rating of users without rated games
+	SetRole(userID uint, role string) error
 }
 
 type userRepository struct{}
@@ -37,4 +42,23 @@ func (r *userRepository) FindByID(id uint) (*models.User, error) {
 		return nil, err
 	}
 	return &user, nil
+}
+
+func (r *userRepository) FindAllIDs() ([]uint, error) {
+	var ids []uint
+	err := database.DB.Model(&models.User{}).Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *userRepository) SavePlacement(user *models.User) error {
+	return database.DB.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"skill_level":          user.SkillLevel,
+		"preferred_difficulty": user.PreferredDifficulty,
+		"placed_at":            user.PlacedAt,
+		"rating":               gorm.Expr("CASE WHEN rating_games = 0 THEN ? ELSE rating END", user.Rating),
+	}).Error
+}
+
+func (r *userRepository) SetRole(userID uint, role string) error {
+	return database.DB.Model(&models.User{}).Where("id = ?", userID).Update("role", role).Error
 }
\ No newline at end of file
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+	"riddles-server/database"
+	"riddles-server/models"
+)
+
+// WrongAnswerCount aggregates the submissions of one normalized wrong answer
+type WrongAnswerCount struct {
+	Normalized      string
+	Example         string // one of the submitted spellings
+	Submissions     int
+	Players         int
+	LastSubmittedAt time.Time
+}
+
+type WrongAnswerRepository interface {
+	Create(answer *models.WrongAnswer) error
+	TopForRiddle(riddleID uint, limit int) ([]WrongAnswerCount, error) // most submitted first
+	FindFirstPerPlayer(riddleID uint, normalized string) ([]models.WrongAnswer, error)
+}
+
+type wrongAnswerRepository struct{}
+
+func NewWrongAnswerRepository() WrongAnswerRepository {
+	return &wrongAnswerRepository{}
+}
+
+func (r *wrongAnswerRepository) Create(answer *models.WrongAnswer) error {
+	return database.DB.Omit("Riddle", "User").Create(answer).Error
+}
+
+func (r *wrongAnswerRepository) TopForRiddle(riddleID uint, limit int) ([]WrongAnswerCount, error) {
+	var counts []WrongAnswerCount
+	err := database.DB.Model(&models.WrongAnswer{}).
+		Select("normalized, MIN(answer) AS example, COUNT(*) AS submissions, COUNT(DISTINCT user_id) AS players, MAX(created_at) AS last_submitted_at").
+		Where("riddle_id = ?", riddleID).
+		Group("normalized").
+		Order("players DESC, submissions DESC, normalized").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// FindFirstPerPlayer returns each player's earliest submission of the answer
+func (r *wrongAnswerRepository) FindFirstPerPlayer(riddleID uint, normalized string) ([]models.WrongAnswer, error) {
+	var answers []models.WrongAnswer
+	err := database.DB.Raw(`
+		SELECT DISTINCT ON (user_id) *
+		FROM wrong_answers
+		WHERE riddle_id = ? AND normalized = ?
+		ORDER BY user_id, created_at`, riddleID, normalized).
+		Scan(&answers).Error
+	return answers, err
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+//go:build rescore
+
+package main
+
+import (
+	"log"
+	"riddles-server/database"
+	"riddles-server/repository"
+	"riddles-server/services"
+)
+
+// Recomputes every user's points with the current scoring rules, appending
+// adjustment entries to the ledger where the totals differ, and rebuilds the
+// leaderboard tables from solve progress
+func main() {
+	// Initialize database
+	database.ConnectDB()
+	database.MigrateDB()
+
+	userRepo := repository.NewUserRepository()
+	scoringService := services.NewScoringService(
+		repository.NewPointsRepository(),
+		repository.NewProgressRepository(),
+		repository.NewRiddleRepository(),
+		repository.NewDailyRiddleRepository(),
+	)
+
+	userIDs, err := userRepo.FindAllIDs()
+	if err != nil {
+		log.Fatal("Failed to load users:", err)
+	}
+
+	adjusted := 0
+	for _, userID := range userIDs {
+		added, err := scoringService.Recompute(userID)
+		if err != nil {
+			log.Printf("Error rescoring user %d: %v", userID, err)
+			continue
+		}
+		adjusted += added
+	}
+
+	log.Printf("Rescoring completed: %d adjustments for %d users", adjusted, len(userIDs))
+
+	leaderboardService := services.NewLeaderboardService(
+		repository.NewLeaderboardRepository(),
+		repository.NewDailyRiddleRepository(),
+	)
+	if err := leaderboardService.Rebuild(); err != nil {
+		log.Fatal("Failed to rebuild leaderboards:", err)
+	}
+
+	log.Println("Leaderboards rebuilt successfully")
+}
\ No newline at end of file
@@ -1,15 +1,23 @@
 package routes
 
 import (
+	"log"
+	"time"
+	"riddles-server/config"
 	"riddles-server/handlers"
 	"riddles-server/middleware"
+	"riddles-server/models"
 	"riddles-server/repository"
 	"riddles-server/services"
+	"riddles-server/storage"
+	"riddles-server/utils"
 
 	"github.com/labstack/echo/v4"
 )
 
 func SetupRoutes(e *echo.Echo) {
+	cfg := config.LoadConfig()
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository()
 	riddleRepo := repository.NewRiddleRepository()
@@ -17,22 +25,121 @@ func SetupRoutes(e *echo.Echo) {
 	favoriteRepo := repository.NewFavoriteRepository()
 	ratingRepo := repository.NewRatingRepository()
 	dailyRiddleRepo := repository.NewDailyRiddleRepository()
+	streakRepo := repository.NewStreakRepository()
+	pointsRepo := repository.NewPointsRepository()
+	leaderboardRepo := repository.NewLeaderboardRepository()
+	achievementRepo := repository.NewAchievementRepository()
+	quizRepo := repository.NewQuizRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	teamRepo := repository.NewTeamRepository()
+	chgkRepo := repository.NewChgkRepository()
+	duelRepo := repository.NewDuelRepository()
+	reviewRepo := repository.NewReviewRepository()
+	eloRepo := repository.NewEloRepository()
+	placementRepo := repository.NewPlacementRepository()
+	recommendationRepo := repository.NewRecommendationRepository()
+	submissionRepo := repository.NewSubmissionRepository()
+	commentRepo := repository.NewCommentRepository()
+	reportRepo := repository.NewReportRepository()
+	notificationRepo := repository.NewNotificationRepository()
+	wrongAnswerRepo := repository.NewWrongAnswerRepository()
+	tagRepo := repository.NewTagRepository()
+	revisionRepo := repository.NewRevisionRepository()
+	mediaRepo := repository.NewMediaRepository()
+
+	// Media files live in a blob store, local or S3-compatible
+	blobStore, err := storage.NewBlobStore(cfg)
+	if err != nil {
+		log.Fatal("Failed to set up media storage:", err)
+	}
 
 	// Initialize services
+	events := services.NewEventBus()
 	authService := services.NewAuthService(userRepo, "riddles_secret_key") // In production, use config
 	userService := services.NewUserService(userRepo, progressRepo)
-	riddleService := services.NewRiddleService(riddleRepo, progressRepo, favoriteRepo, ratingRepo)
+	streakService := services.NewStreakService(streakRepo, dailyRiddleRepo)
+	scoringService := services.NewScoringService(pointsRepo, progressRepo, riddleRepo, dailyRiddleRepo)
+	leaderboardService := services.NewLeaderboardService(leaderboardRepo, dailyRiddleRepo)
+	achievementService := services.NewAchievementService(achievementRepo, streakRepo)
+	mediaService := services.NewMediaService(mediaRepo, riddleRepo, blobStore)
+	riddleService := services.NewRiddleService(riddleRepo, progressRepo, favoriteRepo, ratingRepo, userRepo, mediaService, events)
 	favoriteService := services.NewFavoriteService(favoriteRepo, riddleRepo)
-	ratingService := services.NewRatingService(ratingRepo, riddleRepo)
-	dailyRiddleService := services.NewDailyRiddleService(dailyRiddleRepo)
+	ratingService := services.NewRatingService(ratingRepo, riddleRepo, events)
+	quizService := services.NewQuizService(quizRepo, riddleRepo, mediaService)
+	roomService := services.NewRoomService(riddleRepo, mediaService)
+	teamService := services.NewTeamService(teamRepo, userRepo)
+	chgkService := services.NewChgkService(chgkRepo, teamRepo, riddleRepo, categoryRepo, mediaService)
+	duelService := services.NewDuelService(duelRepo, userRepo, riddleRepo, mediaService)
+	reviewService := services.NewReviewService(reviewRepo, progressRepo, mediaService)
+	eloService := services.NewEloService(eloRepo, userRepo, mediaService)
+	placementService := services.NewPlacementService(placementRepo, userRepo, mediaService)
+	recommendationService := services.NewRecommendationService(recommendationRepo, riddleRepo, mediaService)
+	submissionService := services.NewSubmissionService(submissionRepo, categoryRepo)
+	commentService := services.NewCommentService(commentRepo, riddleRepo, progressRepo, userRepo)
+	notificationService := services.NewNotificationService(notificationRepo)
+	reportService := services.NewReportService(reportRepo, riddleRepo, notificationService)
+	editorService := services.NewEditorService(riddleRepo, revisionRepo, categoryRepo)
+	wrongAnswerService := services.NewWrongAnswerService(wrongAnswerRepo, riddleRepo, progressRepo, editorService, events)
+	tagService := services.NewTagService(tagRepo, riddleRepo)
+	catalogService := services.NewCatalogService(riddleRepo, categoryRepo, tagService)
+	categoryService := services.NewCategoryService(categoryRepo)
+	dailyRiddleService := services.NewDailyRiddleService(dailyRiddleRepo, mediaService)
+	shareService := services.NewShareService(dailyRiddleRepo, progressRepo, cfg.JWTSecret, cfg.PublicURL)
+
+	// Subscribe to player events; achievements go last so they see updated streaks
+	events.Subscribe(services.EventRiddleSolved, func(event services.Event) error {
+		return streakService.RecordDailySolve(event.UserID, event.Riddle.ID, event.At)
+	})
+	events.Subscribe(services.EventRiddleSolved, func(event services.Event) error {
+		return scoringService.AwardSolve(event.Progress, event.Riddle)
+	})
+	events.Subscribe(services.EventRiddleSolved, func(event services.Event) error {
+		return leaderboardService.RecordSolve(event.Progress, event.Riddle)
+	})
+	events.Subscribe(services.EventRiddleSolved, achievementService.HandleEvent)
+	events.Subscribe(services.EventRiddleRated, achievementService.HandleEvent)
+	events.Subscribe(services.EventAnswerWrong, reviewService.HandleEvent)
+	events.Subscribe(services.EventRiddleRevealed, reviewService.HandleEvent)
+	events.Subscribe(services.EventRiddleSolved, eloService.HandleEvent)
+	events.Subscribe(services.EventAnswerWrong, eloService.HandleEvent)
+	events.Subscribe(services.EventRiddleRevealed, eloService.HandleEvent)
+	events.Subscribe(services.EventAnswerWrong, wrongAnswerService.HandleEvent)
+
+	// Background jobs; the daily selection runs often enough to pick the new
+	// set soon after midnight and does nothing once the day has one
+	utils.StartJob("daily riddle selection", 10*time.Minute, utils.SelectDailyRiddles)
+	utils.StartJob("difficulty recalibration", time.Hour, eloService.Recalibrate)
+	utils.StartJob("recommendations", 30*time.Minute, recommendationService.Precompute)
+	utils.StartJob("scheduled publishing", time.Minute, editorService.PublishDue)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	userHandler := handlers.NewUserHandler(userService)
-	riddleHandler := handlers.NewRiddleHandler(riddleService)
+	userHandler := handlers.NewUserHandler(userService, streakService, scoringService, achievementService)
+	riddleHandler := handlers.NewRiddleHandler(riddleService, eloService)
 	favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
 	ratingHandler := handlers.NewRatingHandler(ratingService)
 	dailyRiddleHandler := handlers.NewDailyRiddleHandler(dailyRiddleService)
+	shareHandler := handlers.NewShareHandler(shareService)
+	feedHandler := handlers.NewFeedHandler(dailyRiddleService, cfg.PublicURL)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService)
+	quizHandler := handlers.NewQuizHandler(quizService)
+	roomHandler := handlers.NewRoomHandler(roomService)
+	teamHandler := handlers.NewTeamHandler(teamService)
+	chgkHandler := handlers.NewChgkHandler(chgkService)
+	duelHandler := handlers.NewDuelHandler(duelService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	placementHandler := handlers.NewPlacementHandler(placementService)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+	submissionHandler := handlers.NewSubmissionHandler(submissionService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	wrongAnswerHandler := handlers.NewWrongAnswerHandler(wrongAnswerService)
+	editorHandler := handlers.NewEditorHandler(editorService)
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, riddleService)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService)
@@ -45,17 +152,58 @@ func SetupRoutes(e *echo.Echo) {
 		auth.POST("/refresh", authHandler.Refresh)
 	}
 
-	riddles := e.Group("/api/riddles")
+	riddles := e.Group("/api/riddles", authMiddleware.AuthOptional)
 	{
 		riddles.GET("", riddleHandler.GetAllRiddles)
+		riddles.GET("/next", riddleHandler.GetNextRiddle)
+		riddles.GET("/random", riddleHandler.GetRandomRiddle)
+		riddles.GET("/recommended", recommendationHandler.GetRecommended)
 		riddles.GET("/:id", riddleHandler.GetRiddleByID)
 		riddles.POST("/:id/answer", riddleHandler.CheckAnswer)
 	}
 
+	// Categories are addressed by ID or slug
+	categories := e.Group("/api/categories", authMiddleware.AuthOptional)
+	{
+		categories.GET("", categoryHandler.GetCategories)
+		categories.GET("/:ref", categoryHandler.GetCategory)
+		categories.GET("/:ref/riddles", categoryHandler.GetCategoryRiddles)
+	}
+
+	e.GET("/api/tags", tagHandler.GetTags)
+
 	dailyRiddle := e.Group("/api/daily-riddle")
 	{
 		dailyRiddle.GET("", dailyRiddleHandler.GetTodayRiddle)
 		dailyRiddle.GET("/:date", dailyRiddleHandler.GetRiddleByDate)
+		dailyRiddle.GET("/:date/answers", dailyRiddleHandler.GetAnswersByDate)
+	}
+
+	leaderboards := e.Group("/api/leaderboards", authMiddleware.AuthOptional)
+	{
+		leaderboards.GET("/all-time", leaderboardHandler.GetAllTime)
+		leaderboards.GET("/weekly", leaderboardHandler.GetWeekly)
+		leaderboards.GET("/monthly", leaderboardHandler.GetMonthly)
+		leaderboards.GET("/category/:id", leaderboardHandler.GetCategory)
+		leaderboards.GET("/daily-speed", leaderboardHandler.GetDailySpeed)
+	}
+
+	// Quiz room WebSocket; players join by code and reconnect with their key
+	e.GET("/ws/rooms/:code", roomHandler.Connect)
+
+	feeds := e.Group("/feeds")
+	{
+		feeds.GET("/daily.atom", feedHandler.GetAtomFeed)
+		feeds.GET("/daily.rss", feedHandler.GetRSSFeed)
+		feeds.GET("/daily.ics", feedHandler.GetICalendar)
+	}
+
+	// Public share pages
+	e.GET("/share/:token", shareHandler.ShowSharePage)
+
+	// Locally stored media, reachable through signed URLs only
+	if localStore, ok := blobStore.(*storage.LocalStore); ok {
+		e.GET("/media/*", handlers.NewLocalMediaHandler(localStore).Serve)
 	}
 
 	// Protected routes
@@ -65,6 +213,75 @@ func SetupRoutes(e *echo.Echo) {
 	// User routes
 	protected.GET("/users/profile", userHandler.GetProfile)
 	protected.GET("/users/stats", userHandler.GetUserStats)
+	protected.GET("/users/streak", userHandler.GetStreak)
+	protected.GET("/users/points", userHandler.GetPointsLedger)
+	protected.GET("/users/achievements", userHandler.GetAchievements)
+
+	// Placement test routes
+	protected.POST("/placement", placementHandler.StartTest)
+	protected.GET("/placement", placementHandler.GetTest)
+	protected.POST("/placement/answer", placementHandler.SubmitAnswer)
+
+	// Riddle routes
+	protected.POST("/riddles/:id/hint", riddleHandler.GetHint)
+	protected.POST("/riddles/:id/reveal", riddleHandler.RevealAnswer)
+
+	// Comment routes; threads are only visible once the riddle is solved or revealed
+	protected.GET("/riddles/:id/comments", commentHandler.GetComments)
+	protected.POST("/riddles/:id/comments", commentHandler.AddComment)
+	protected.PUT("/comments/:id", commentHandler.EditComment)
+	protected.DELETE("/comments/:id", commentHandler.DeleteComment)
+
+	// Report routes
+	protected.POST("/riddles/:id/reports", reportHandler.Report)
+	protected.GET("/reports/mine", reportHandler.GetMyReports)
+
+	// Notification routes
+	protected.GET("/notifications", notificationHandler.GetNotifications)
+	protected.POST("/notifications/read", notificationHandler.MarkAllRead)
+	protected.POST("/notifications/:id/read", notificationHandler.MarkRead)
+
+	// Review routes
+	protected.GET("/review/due", reviewHandler.GetDue)
+	protected.POST("/review/:riddle_id", reviewHandler.SubmitReview)
+
+	// Daily riddle routes
+	protected.GET("/daily-riddle/share", shareHandler.GetDailyShare)
+
+	// Quiz routes
+	protected.POST("/quizzes", quizHandler.CreateQuiz)
+	protected.GET("/quizzes/:id", quizHandler.GetQuiz)
+	protected.POST("/quizzes/:id/questions/:position/answer", quizHandler.SubmitAnswer)
+	protected.POST("/quizzes/:id/finish", quizHandler.FinishQuiz)
+
+	// Quiz room routes
+	protected.POST("/rooms", roomHandler.CreateRoom)
+
+	// Team routes
+	protected.POST("/teams", teamHandler.CreateTeam)
+	protected.GET("/teams/mine", teamHandler.GetMyTeams)
+	protected.GET("/teams/:id", teamHandler.GetTeam)
+	protected.POST("/teams/:id/members", teamHandler.AddMember)
+	protected.DELETE("/teams/:id/members/:user_id", teamHandler.RemoveMember)
+	protected.PUT("/teams/:id/captain", teamHandler.SetCaptain)
+
+	// ЧГК game routes
+	protected.POST("/chgk/games", chgkHandler.CreateGame)
+	protected.GET("/chgk/games/:id", chgkHandler.GetGame)
+	protected.POST("/chgk/games/:id/teams", chgkHandler.RegisterTeam)
+	protected.POST("/chgk/games/:id/next", chgkHandler.NextQuestion)
+	protected.POST("/chgk/games/:id/answer", chgkHandler.SubmitAnswer)
+	protected.GET("/chgk/games/:id/results", chgkHandler.GetResults)
+
+	// Duel routes
+	protected.POST("/duels", duelHandler.CreateDuel)
+	protected.GET("/duels", duelHandler.ListDuels)
+	protected.GET("/duels/:id", duelHandler.GetDuel)
+	protected.POST("/duels/:id/accept", duelHandler.AcceptDuel)
+	protected.POST("/duels/:id/decline", duelHandler.DeclineDuel)
+	protected.POST("/duels/:id/start", duelHandler.StartDuel)
+	protected.POST("/duels/:id/answer", duelHandler.SubmitAnswer)
+	protected.POST("/duels/:id/skip", duelHandler.SkipRiddle)
 
 	// Favorite routes
 	protected.POST("/favorites/:riddle_id", favoriteHandler.AddFavorite)
@@ -73,4 +290,49 @@ func SetupRoutes(e *echo.Echo) {
 	// Rating routes
 	protected.POST("/ratings/:riddle_id", ratingHandler.RateRiddle)
 	protected.DELETE("/ratings/:riddle_id", ratingHandler.RemoveRating)
+
+	// Submission routes
+	protected.POST("/submissions", submissionHandler.Submit)
+	protected.GET("/submissions/mine", submissionHandler.GetMySubmissions)
+
+	// Moderation routes
+	moderation := protected.Group("/moderation", authMiddleware.RequireRole(models.RoleModerator))
+	{
+		moderation.GET("/submissions", submissionHandler.GetQueue)
+		moderation.POST("/submissions/:id/approve", submissionHandler.Approve)
+		moderation.POST("/submissions/:id/reject", submissionHandler.Reject)
+		moderation.DELETE("/comments/:id", commentHandler.RemoveComment)
+		moderation.GET("/reports", reportHandler.GetQueue)
+		moderation.POST("/riddles/:id/reports/resolve", reportHandler.Resolve)
+	}
+
+	// Editor routes
+	editor := protected.Group("/editor", authMiddleware.RequireRole(models.RoleEditor))
+	{
+		editor.GET("/riddles", editorHandler.ListRiddles)
+		editor.POST("/riddles", editorHandler.CreateRiddle)
+		editor.PUT("/riddles/:id", editorHandler.UpdateRiddle)
+		editor.PUT("/riddles/:id/status", editorHandler.SetStatus)
+		editor.DELETE("/riddles/:id", editorHandler.DeleteRiddle)
+		editor.GET("/riddles/:id/revisions", editorHandler.GetHistory)
+		editor.POST("/riddles/:id/revisions/:version/rollback", editorHandler.Rollback)
+		editor.GET("/riddles/:id/wrong-answers", wrongAnswerHandler.GetTopWrongAnswers)
+		editor.POST("/riddles/:id/wrong-answers/promote", wrongAnswerHandler.Promote)
+		editor.GET("/riddles/:id/media", mediaHandler.GetMedia)
+		editor.POST("/riddles/:id/media", mediaHandler.Upload, middleware.MediaUploadLimit())
+		editor.DELETE("/media/:id", mediaHandler.Delete)
+	}
+
+	// Admin routes
+	admin := protected.Group("/admin", authMiddleware.RequireRole(models.RoleAdmin))
+	{
+		admin.POST("/categories", categoryHandler.CreateCategory)
+		admin.PUT("/categories/:id", categoryHandler.UpdateCategory)
+		admin.POST("/tags", tagHandler.CreateTag)
+		admin.PUT("/tags/:id", tagHandler.RenameTag)
+		admin.DELETE("/tags/:id", tagHandler.DeleteTag)
+		admin.PUT("/riddles/:id/tags", tagHandler.SetRiddleTags)
+		admin.GET("/riddles/export", catalogHandler.Export)
+		admin.POST("/riddles/import", catalogHandler.Import)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+// Criteria kinds an achievement can be defined with
+const (
+	CriteriaSolvedCount      = "solved_count"      // Threshold solves, optionally of one Difficulty
+	CriteriaCategoryComplete = "category_complete" // every riddle of Category solved
+	CriteriaNoHintSolves     = "no_hint_solves"    // Threshold solves without hints
+	CriteriaDailyStreak      = "daily_streak"      // longest daily streak of Threshold days
+	CriteriaRatingsCount     = "ratings_count"     // Threshold riddles rated
+)
+
+type AchievementCriteria struct {
+	Kind       string `json:"kind"`
+	Threshold  int    `json:"threshold,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+	Category   string `json:"category,omitempty"`
+}
+
+type Achievement struct {
+	Code        string              `json:"code"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Criteria    AchievementCriteria `json:"criteria"`
+}
+
+// Achievements lists every badge; adding one here is all it takes to award it
+var Achievements = []Achievement{
+	{
+		Code:        "first_solve",
+		Name:        "Первый шаг",
+		Description: "Разгадать первую загадку",
+		Criteria:    AchievementCriteria{Kind: CriteriaSolvedCount, Threshold: 1},
+	},
+	{
+		Code:        "solved_50",
+		Name:        "Полсотни",
+		Description: "Разгадать 50 загадок",
+		Criteria:    AchievementCriteria{Kind: CriteriaSolvedCount, Threshold: 50},
+	},
+	{
+		Code:        "hard_10",
+		Name:        "Крепкий орешек",
+		Description: "Разгадать 10 сложных загадок",
+		Criteria:    AchievementCriteria{Kind: CriteriaSolvedCount, Threshold: 10, Difficulty: "hard"},
+	},
+	{
+		Code:        "logic_complete",
+		Name:        "Логик",
+		Description: "Разгадать все загадки категории «Логика»",
+		Criteria:    AchievementCriteria{Kind: CriteriaCategoryComplete, Category: "Логика"},
+	},
+	{
+		Code:        "streak_30",
+		Name:        "Месяц без пропусков",
+		Description: "Серия загадок дня длиной 30 дней",
+		Criteria:    AchievementCriteria{Kind: CriteriaDailyStreak, Threshold: 30},
+	},
+	{
+		Code:        "no_hints",
+		Name:        "Без подсказок",
+		Description: "Разгадать загадку, не используя подсказки",
+		Criteria:    AchievementCriteria{Kind: CriteriaNoHintSolves, Threshold: 1},
+	},
+	{
+		Code:        "critic",
+		Name:        "Критик",
+		Description: "Оценить 10 загадок",
+		Criteria:    AchievementCriteria{Kind: CriteriaRatingsCount, Threshold: 10},
+	},
+}
+
+// Which criteria kinds can change after each event
+var achievementEventCriteria = map[EventType][]string{
+	EventRiddleSolved: {CriteriaSolvedCount, CriteriaCategoryComplete, CriteriaNoHintSolves, CriteriaDailyStreak},
+	EventRiddleRated:  {CriteriaRatingsCount},
+}
+
+type AchievementService interface {
+	HandleEvent(event Event) error
+	GetUserAchievements(userID uint) ([]AchievementStatus, error)
+	Evaluate(userID uint) (int, error) // checks every achievement, returns the number newly awarded
+}
+
+type AchievementStatus struct {
+	Achievement
+	Awarded   bool       `json:"awarded"`
+	AwardedAt *time.Time `json:"awarded_at"`
+}
+
+type achievementService struct {
+	achievementRepo repository.AchievementRepository
+	streakRepo      repository.StreakRepository
+}
+
+func NewAchievementService(achievementRepo repository.AchievementRepository, streakRepo repository.StreakRepository) AchievementService {
+	return &achievementService{
+		achievementRepo: achievementRepo,
+		streakRepo:      streakRepo,
+	}
+}
+
+func (s *achievementService) HandleEvent(event Event) error {
+	kinds := achievementEventCriteria[event.Type]
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	_, err := s.evaluate(event.UserID, kinds, event.At)
+	return err
+}
+
+func (s *achievementService) GetUserAchievements(userID uint) ([]AchievementStatus, error) {
+	awarded, err := s.awardedAt(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]AchievementStatus, len(Achievements))
+	for i, achievement := range Achievements {
+		statuses[i] = AchievementStatus{Achievement: achievement}
+		if at, ok := awarded[achievement.Code]; ok {
+			statuses[i].Awarded = true
+			statuses[i].AwardedAt = &at
+		}
+	}
+
+	return statuses, nil
+}
+
+func (s *achievementService) Evaluate(userID uint) (int, error) {
+	return s.evaluate(userID, nil, time.Now())
+}
+
+// evaluate awards the missing achievements whose criteria kind is in kinds (all when nil)
+func (s *achievementService) evaluate(userID uint, kinds []string, at time.Time) (int, error) {
+	awarded, err := s.awardedAt(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, achievement := range Achievements {
+		if _, ok := awarded[achievement.Code]; ok {
+			continue
+		}
+		if kinds != nil && !slices.Contains(kinds, achievement.Criteria.Kind) {
+			continue
+		}
+
+		met, err := s.criteriaMet(userID, achievement.Criteria)
+		if err != nil {
+			return count, err
+		}
+		if !met {
+			continue
+		}
+
+		err = s.achievementRepo.Award(&models.UserAchievement{
+			UserID:    userID,
+			Code:      achievement.Code,
+			AwardedAt: at,
+		})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (s *achievementService) criteriaMet(userID uint, criteria AchievementCriteria) (bool, error) {
+	switch criteria.Kind {
+	case CriteriaSolvedCount:
+		solved, err := s.achievementRepo.CountSolved(userID, criteria.Difficulty)
+		return solved >= criteria.Threshold, err
+	case CriteriaCategoryComplete:
+		solved, total, err := s.achievementRepo.GetCategoryCompletion(userID, criteria.Category)
+		return total > 0 && solved >= total, err
+	case CriteriaNoHintSolves:
+		solved, err := s.achievementRepo.CountNoHintSolves(userID)
+		return solved >= criteria.Threshold, err
+	case CriteriaDailyStreak:
+		streak, err := s.streakRepo.FindByUserID(userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return streak.LongestStreak >= criteria.Threshold, nil
+	case CriteriaRatingsCount:
+		rated, err := s.achievementRepo.CountRatings(userID)
+		return rated >= criteria.Threshold, err
+	}
+
+	return false, errors.New("unknown achievement criteria: " + criteria.Kind)
+}
+
+func (s *achievementService) awardedAt(userID uint) (map[string]time.Time, error) {
+	achievements, err := s.achievementRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	awarded := make(map[string]time.Time, len(achievements))
+	for _, achievement := range achievements {
+		awarded[achievement.Code] = achievement.AwardedAt
+	}
+	return awarded, nil
+}
\ No newline at end of file
@@ -8,7 +8,6 @@ import (
 	"riddles-server/utils"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService interface {
@@ -16,6 +15,7 @@ type AuthService interface {
 	Login(email, password string) (*models.User, string, string, error) // user, access token, refresh token
 	RefreshToken(refreshToken string) (string, string, error)           // new access token, new refresh token
 	ValidateAccessToken(tokenString string) (*jwt.Token, error)
+	GetRole(userID uint) (string, error)
 }
 
 type authService struct {
@@ -139,6 +139,15 @@ func (s *authService) ValidateAccessToken(tokenString string) (*jwt.Token, error
 	return token, nil
 }
 
+// GetRole reads the role from the database so role changes apply without a new token
+func (s *authService) GetRole(userID uint) (string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
 func (s *authService) generateAccessToken(userID uint) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+// MaxImportRecords caps the size of one import
+const MaxImportRecords = 1000
+
+var (
+	ErrImportInvalid  = errors.New("import has invalid records")
+	ErrImportTooLarge = errors.New("an import can have at most 1000 riddles")
+	ErrImportEmpty    = errors.New("import has no riddles")
+)
+
+// CatalogService moves riddles in and out of the database in bulk
+type CatalogService interface {
+	Export() ([]RiddleRecord, error)
+	Import(records []RiddleRecord) (*ImportResult, error)
+}
+
+// RiddleRecord is a riddle in the import/export formats; the category and
+// tags are referenced by name. Records without a type are text riddles.
+type RiddleRecord struct {
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Answer          string   `json:"answer"`
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+	Explanation     string   `json:"explanation,omitempty"`
+	Category        string   `json:"category"`
+	Difficulty      string   `json:"difficulty"`
+	Tags            []string `json:"tags,omitempty"`
+	RiddleFormat
+}
+
+type ImportResult struct {
+	Imported int           `json:"imported"`
+	Errors   []ImportError `json:"errors,omitempty"`
+}
+
+type ImportError struct {
+	Record  int    `json:"record"` // 1-based position in the import
+	Message string `json:"message"`
+}
+
+type catalogService struct {
+	riddleRepo   repository.RiddleRepository
+	categoryRepo repository.CategoryRepository
+	tagService   TagService
+}
+
+func NewCatalogService(riddleRepo repository.RiddleRepository, categoryRepo repository.CategoryRepository, tagService TagService) CatalogService {
+	return &catalogService{
+		riddleRepo:   riddleRepo,
+		categoryRepo: categoryRepo,
+		tagService:   tagService,
+	}
+}
+
+func (s *catalogService) Export() ([]RiddleRecord, error) {
+	riddles, err := s.riddleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]RiddleRecord, len(riddles))
+	for i, riddle := range riddles {
+		records[i] = RiddleRecord{
+			Title:           riddle.Title,
+			Description:     riddle.Description,
+			Answer:          riddle.Answer,
+			AcceptedAnswers: acceptedAnswers(&riddle)[1:],
+			Explanation:     riddle.Explanation,
+			Category:        riddle.Category.Name,
+			Difficulty:      riddle.Difficulty,
+			RiddleFormat:    formatOf(&riddle),
+		}
+		for _, tag := range riddle.Tags {
+			records[i].Tags = append(records[i].Tags, tag.Name)
+		}
+	}
+	return records, nil
+}
+
+// Import validates every record first and creates nothing unless all of them
+// are valid. The result lists the problems of the invalid ones.
+func (s *catalogService) Import(records []RiddleRecord) (*ImportResult, error) {
+	if len(records) == 0 {
+		return nil, ErrImportEmpty
+	}
+	if len(records) > MaxImportRecords {
+		return nil, ErrImportTooLarge
+	}
+
+	result := &ImportResult{}
+	categories := make(map[string]uint)
+	riddles := make([]models.Riddle, len(records))
+	for i, record := range records {
+		riddle, err := s.toRiddle(record, categories)
+		if errors.Is(err, ErrRiddleInvalid) || errors.Is(err, ErrTagInvalid) || errors.Is(err, ErrTooManyTags) {
+			result.Errors = append(result.Errors, ImportError{Record: i + 1, Message: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		riddles[i] = *riddle
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrImportInvalid
+	}
+
+	for i := range riddles {
+		tags, err := s.tagService.ResolveTags(records[i].Tags)
+		if err != nil {
+			return nil, err
+		}
+		riddles[i].Tags = tags
+	}
+
+	if err := s.riddleRepo.CreateBatch(riddles); err != nil {
+		return nil, err
+	}
+	result.Imported = len(riddles)
+	return result, nil
+}
+
+func (s *catalogService) toRiddle(record RiddleRecord, categories map[string]uint) (*models.Riddle, error) {
+	input := RiddleInput{
+		Title:       record.Title,
+		Description: record.Description,
+		Answer:      record.Answer,
+		Explanation: record.Explanation,
+		Difficulty:  record.Difficulty,
+	}.trimmed()
+	format := record.RiddleFormat.normalized()
+	if err := input.check(format); err != nil {
+		return nil, err
+	}
+	accepted := cleanAcceptedAnswers(record.AcceptedAnswers)
+	if len(accepted) > 0 && format.Type != models.RiddleText {
+		return nil, fmt.Errorf("%w: only text riddles have accepted answers", ErrRiddleInvalid)
+	}
+
+	if len(record.Tags) > 0 {
+		// Only checked here; the tags are created once every record is valid
+		if _, err := resolveTagNames(record.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.TrimSpace(record.Category)
+	categoryID, ok := categories[name]
+	if !ok {
+		category, err := s.categoryRepo.FindByName(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: category %q not found", ErrRiddleInvalid, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		categoryID = category.ID
+		categories[name] = categoryID
+	}
+
+	riddle := &models.Riddle{
+		Title:           input.Title,
+		Description:     input.Description,
+		Answer:          input.Answer,
+		AcceptedAnswers: strings.Join(accepted, "\n"),
+		Explanation:     input.Explanation,
+		CategoryID:      categoryID,
+		Difficulty:      input.Difficulty,
+	}
+	format.applyTo(riddle)
+	return riddle, nil
+}
\ No newline at end of file
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"riddles-server/models"
+	"riddles-server/repository"
+	"riddles-server/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrCategoryInvalid   = errors.New("invalid category")
+	ErrCategoryNameTaken = errors.New("category name is already taken")
+	ErrCategorySlugTaken = errors.New("category slug is already taken")
+	ErrCategoryCycle     = errors.New("a category cannot be nested inside itself")
+)
+
+type CategoryService interface {
+	GetTree() ([]*CategoryNode, error)
+	GetCategory(ref string) (*CategoryDetail, error)
+	GetSubtreeIDs(ref string) ([]uint, error) // the category and all of its descendants
+	CreateCategory(input CategoryInput) (*models.Category, error)
+	UpdateCategory(categoryID uint, input CategoryInput) (*models.Category, error)
+	AssignSlugs() (int, error) // gives a slug to categories created before slugs existed or with a digit-only one
+}
+
+type CategoryInput struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"` // generated from the name when empty
+	ParentID    *uint  `json:"parent_id"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+type CategoryNode struct {
+	models.Category
+	Riddles  int             `json:"riddles"` // including subcategories
+	Children []*CategoryNode `json:"children"`
+}
+
+type CategoryDetail struct {
+	*CategoryNode
+	Path []models.Category `json:"path"` // ancestors, root first
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *categoryService) GetTree() ([]*CategoryNode, error) {
+	roots, _, err := s.buildTree()
+	return roots, err
+}
+
+// GetCategory looks the category up by ID or slug
+func (s *categoryService) GetCategory(ref string) (*CategoryDetail, error) {
+	_, nodes, err := s.buildTree()
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := findCategoryNode(nodes, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &CategoryDetail{CategoryNode: node, Path: []models.Category{}}
+	for parentID := node.ParentID; parentID != nil; {
+		parent := nodes[*parentID]
+		detail.Path = append([]models.Category{parent.Category}, detail.Path...)
+		parentID = parent.ParentID
+	}
+	return detail, nil
+}
+
+func (s *categoryService) GetSubtreeIDs(ref string) ([]uint, error) {
+	_, nodes, err := s.buildTree()
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := findCategoryNode(nodes, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	var walk func(node *CategoryNode)
+	walk = func(node *CategoryNode) {
+		ids = append(ids, node.ID)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return ids, nil
+}
+
+func (s *categoryService) CreateCategory(input CategoryInput) (*models.Category, error) {
+	category := &models.Category{}
+	if err := s.apply(category, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory replaces the category's fields. The slug is kept when the
+// input has none, so existing links keep working after a rename.
+func (s *categoryService) UpdateCategory(categoryID uint, input CategoryInput) (*models.Category, error) {
+	category, err := s.categoryRepo.FindByID(categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	if strings.TrimSpace(input.Slug) == "" {
+		input.Slug = category.Slug
+	}
+	if err := s.apply(category, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *categoryService) AssignSlugs() (int, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	assigned := 0
+	for i := range categories {
+		// Digit-only slugs from before they were prefixed are replaced too
+		if categories[i].Slug != "" && !isIDRef(categories[i].Slug) {
+			continue
+		}
+		slug, err := s.uniqueSlug(nameSlug(categories[i].Name), categories[i].ID)
+		if err != nil {
+			return assigned, err
+		}
+		categories[i].Slug = slug
+		if err := s.categoryRepo.Update(&categories[i]); err != nil {
+			return assigned, err
+		}
+		assigned++
+	}
+	return assigned, nil
+}
+
+// apply validates the input and copies it onto the category
+func (s *categoryService) apply(category *models.Category, input CategoryInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len([]rune(name)) > 50 {
+		return fmt.Errorf("%w: name must be 1-50 characters", ErrCategoryInvalid)
+	}
+	if len([]rune(input.Icon)) > 100 {
+		return fmt.Errorf("%w: icon is too long", ErrCategoryInvalid)
+	}
+
+	existing, err := s.categoryRepo.FindByName(name)
+	if err == nil && existing.ID != category.ID {
+		return ErrCategoryNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if input.ParentID != nil {
+		if err := s.checkParent(category.ID, *input.ParentID); err != nil {
+			return err
+		}
+	}
+
+	slug := utils.Slugify(input.Slug)
+	if slug == "" {
+		slug, err = s.uniqueSlug(nameSlug(name), category.ID)
+		if err != nil {
+			return err
+		}
+	} else if isIDRef(slug) {
+		return fmt.Errorf("%w: slug must not be just digits, those are read as IDs", ErrCategoryInvalid)
+	} else if taken, err := s.slugTaken(slug, category.ID); err != nil {
+		return err
+	} else if taken {
+		return ErrCategorySlugTaken
+	}
+	if slug == "" {
+		return fmt.Errorf("%w: name must contain letters or digits", ErrCategoryInvalid)
+	}
+
+	category.Name = name
+	category.Slug = slug
+	category.ParentID = input.ParentID
+	category.Description = strings.TrimSpace(input.Description)
+	category.Icon = strings.TrimSpace(input.Icon)
+	category.SortOrder = input.SortOrder
+	return nil
+}
+
+// checkParent makes sure the parent exists and isn't the category itself or one of its descendants
+func (s *categoryService) checkParent(categoryID, parentID uint) error {
+	for id := parentID; ; {
+		if id == categoryID {
+			return ErrCategoryCycle
+		}
+		parent, err := s.categoryRepo.FindByID(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: parent category not found", ErrCategoryInvalid)
+		}
+		if err != nil {
+			return err
+		}
+		if parent.ParentID == nil {
+			return nil
+		}
+		id = *parent.ParentID
+	}
+}
+
+// nameSlug derives a slug from the name; names of digits only, like "2024",
+// get a prefix so their slug isn't read as an ID
+func nameSlug(name string) string {
+	slug := utils.Slugify(name)
+	if isIDRef(slug) {
+		slug = "category-" + slug
+	}
+	return slug
+}
+
+// uniqueSlug appends -2, -3... until the slug is free
+func (s *categoryService) uniqueSlug(base string, categoryID uint) (string, error) {
+	if base == "" {
+		return "", nil
+	}
+	for n := 1; ; n++ {
+		slug := base
+		if n > 1 {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken, err := s.slugTaken(slug, categoryID)
+		if err != nil || !taken {
+			return slug, err
+		}
+	}
+}
+
+func (s *categoryService) slugTaken(slug string, categoryID uint) (bool, error) {
+	existing, err := s.categoryRepo.FindBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return existing.ID != categoryID, nil
+}
+
+// buildTree links every category to its children and sums riddle counts up
+// the tree. It returns the roots and all nodes by ID.
+func (s *categoryService) buildTree() ([]*CategoryNode, map[uint]*CategoryNode, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	counts, err := s.categoryRepo.CountRiddles()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodes := make(map[uint]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category, Children: []*CategoryNode{}}
+	}
+
+	// Categories come in display order, so children stay sorted
+	roots := []*CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil && nodes[*category.ParentID] != nil {
+			parent := nodes[*category.ParentID]
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	var sum func(node *CategoryNode) int
+	sum = func(node *CategoryNode) int {
+		node.Riddles = counts[node.ID]
+		for _, child := range node.Children {
+			node.Riddles += sum(child)
+		}
+		return node.Riddles
+	}
+	for _, root := range roots {
+		sum(root)
+	}
+
+	return roots, nodes, nil
+}
+
+// isIDRef reports whether findCategoryNode reads the ref as an ID
+func isIDRef(ref string) bool {
+	_, err := strconv.ParseUint(ref, 10, 64)
+	return err == nil
+}
+
+// findCategoryNode resolves a numeric ID or a slug
+func findCategoryNode(nodes map[uint]*CategoryNode, ref string) (*CategoryNode, error) {
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		if node, ok := nodes[uint(id)]; ok {
+			return node, nil
+		}
+		return nil, ErrCategoryNotFound
+	}
+	for _, node := range nodes {
+		if node.Slug == ref {
+			return node, nil
+		}
+	}
+	return nil, ErrCategoryNotFound
+}
\ No newline at end of file
@@ -0,0 +1,487 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// ChgkCategory is the category game packets are drawn from
+	ChgkCategory = "Что? Где? Когда?"
+	// ChgkDiscussionTime is the minute teams get to discuss a question
+	ChgkDiscussionTime = 60 * time.Second
+	// chgkHandInTime is the extra time to hand in the answer after the minute runs out
+	chgkHandInTime    = 10 * time.Second
+	defaultChgkPacket = 12
+	maxChgkPacket     = 36
+)
+
+var (
+	ErrChgkGameNotFound       = errors.New("game not found")
+	ErrNotChgkHost            = errors.New("only the host can run the game")
+	ErrChgkRegistrationClosed = errors.New("registration is closed")
+	ErrChgkTeamRegistered     = errors.New("team is already registered")
+	ErrChgkPlayerRegistered   = errors.New("a team member already plays for another team in this game")
+	ErrChgkNoTeams            = errors.New("no teams are registered")
+	ErrChgkRoundInProgress    = errors.New("current question is still open")
+	ErrChgkGameFinished       = errors.New("game is finished")
+	ErrChgkNoOpenQuestion     = errors.New("no question is open for answers")
+	ErrChgkNotCaptain         = errors.New("only a registered team captain can answer")
+	ErrChgkAlreadyAnswered    = errors.New("team already answered this question")
+)
+
+type ChgkService interface {
+	CreateGame(userID uint, options ChgkGameOptions) (*ChgkGameView, error)
+	GetGame(userID, gameID uint) (*ChgkGameView, error)
+	RegisterTeam(userID, gameID, teamID uint) (*ChgkGameView, error)
+	NextQuestion(userID, gameID uint) (*ChgkGameView, error)
+	SubmitAnswer(userID, gameID uint, answer string) (*ChgkAnswerResult, error)
+	GetResults(gameID uint) (*ChgkResults, error)
+}
+
+type ChgkGameOptions struct {
+	Title     string `json:"title"`
+	Questions int    `json:"questions"`
+}
+
+type ChgkGameView struct {
+	ID             uint              `json:"id"`
+	Title          string            `json:"title"`
+	HostID         uint              `json:"host_id"`
+	Status         string            `json:"status"`
+	TotalQuestions int               `json:"total_questions"`
+	Teams          []ChgkTeamView    `json:"teams"`
+	Current        *ChgkQuestionView `json:"current,omitempty"`
+	MyTeamID       *uint             `json:"my_team_id,omitempty"`
+}
+
+type ChgkTeamView struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// ChgkQuestionView hides the correct answer until answers are no longer accepted
+type ChgkQuestionView struct {
+	Position         int                  `json:"position"`
+	Title            string               `json:"title"`
+	Description      string               `json:"description"`
+	Type             string               `json:"type"`
+	Payload          models.RiddlePayload `json:"payload"`
+	Media            []models.RiddleMedia `json:"media,omitempty"`
+	OpenedAt         time.Time            `json:"opened_at"`
+	DiscussionEndsAt time.Time            `json:"discussion_ends_at"`
+	ClosesAt         time.Time            `json:"closes_at"`
+	RemainingSeconds int                  `json:"remaining_seconds"`
+	Closed           bool                 `json:"closed"`
+	CorrectAnswer    string               `json:"correct_answer,omitempty"`
+	MyAnswer         string               `json:"my_answer,omitempty"`
+}
+
+type ChgkAnswerResult struct {
+	Position    int       `json:"position"`
+	TeamID      uint      `json:"team_id"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// ChgkResults is the game's results table. Teams are ranked by correct
+// answers, then by rating: every question is worth one point more than the
+// number of teams that missed it. Tied teams share a place ("2-3").
+type ChgkResults struct {
+	GameID    uint            `json:"game_id"`
+	Title     string          `json:"title"`
+	Status    string          `json:"status"`
+	Questions int             `json:"questions"` // closed questions included in the table
+	Rows      []ChgkResultRow `json:"rows"`
+}
+
+type ChgkResultRow struct {
+	Place    string `json:"place"`
+	TeamID   uint   `json:"team_id"`
+	TeamName string `json:"team_name"`
+	Correct  int    `json:"correct"`
+	Rating   int    `json:"rating"`
+	Marks    []bool `json:"marks"` // per closed question, in packet order
+}
+
+type chgkService struct {
+	chgkRepo     repository.ChgkRepository
+	teamRepo     repository.TeamRepository
+	riddleRepo   repository.RiddleRepository
+	categoryRepo repository.CategoryRepository
+	mediaService MediaService
+}
+
+func NewChgkService(chgkRepo repository.ChgkRepository, teamRepo repository.TeamRepository, riddleRepo repository.RiddleRepository, categoryRepo repository.CategoryRepository, mediaService MediaService) ChgkService {
+	return &chgkService{
+		chgkRepo:     chgkRepo,
+		teamRepo:     teamRepo,
+		riddleRepo:   riddleRepo,
+		categoryRepo: categoryRepo,
+		mediaService: mediaService,
+	}
+}
+
+func (s *chgkService) CreateGame(userID uint, options ChgkGameOptions) (*ChgkGameView, error) {
+	count := options.Questions
+	if count <= 0 {
+		count = defaultChgkPacket
+	}
+	if count > maxChgkPacket {
+		count = maxChgkPacket
+	}
+
+	category, err := s.categoryRepo.FindByName(ChgkCategory)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotEnoughRiddles
+		}
+		return nil, err
+	}
+
+	riddles, err := s.riddleRepo.FindRandom(category.ID, "", count)
+	if err != nil {
+		return nil, err
+	}
+	if len(riddles) == 0 {
+		return nil, ErrNotEnoughRiddles
+	}
+
+	title := options.Title
+	if title == "" {
+		title = fmt.Sprintf("Игра от %s", time.Now().Format("02.01.2006"))
+	}
+
+	game := &models.ChgkGame{
+		Title:  title,
+		HostID: userID,
+		Status: models.ChgkRegistration,
+	}
+	for i, riddle := range riddles {
+		game.Questions = append(game.Questions, models.ChgkGameQuestion{
+			Position: i + 1,
+			RiddleID: riddle.ID,
+		})
+	}
+
+	if err := s.chgkRepo.Create(game); err != nil {
+		return nil, err
+	}
+
+	return s.GetGame(userID, game.ID)
+}
+
+func (s *chgkService) GetGame(userID, gameID uint) (*ChgkGameView, error) {
+	game, err := s.findGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	var answers []models.ChgkAnswer
+	if teamID := captainTeamID(game, userID); teamID != nil && game.CurrentPosition > 0 {
+		if answers, err = s.chgkRepo.FindAnswers(game.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	view := newChgkGameView(game, userID, answers, time.Now())
+	if view.Current != nil {
+		s.mediaService.Sign(view.Current.Media)
+	}
+	return view, nil
+}
+
+func (s *chgkService) RegisterTeam(userID, gameID, teamID uint) (*ChgkGameView, error) {
+	game, err := s.findGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status != models.ChgkRegistration {
+		return nil, ErrChgkRegistrationClosed
+	}
+
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	if team.CaptainID != userID {
+		return nil, ErrNotTeamCaptain
+	}
+
+	// A player can only play for one team per game
+	for _, registered := range game.Teams {
+		if registered.TeamID == team.ID {
+			return nil, ErrChgkTeamRegistered
+		}
+		for _, member := range team.Members {
+			if isTeamMember(&registered.Team, member.UserID) {
+				return nil, ErrChgkPlayerRegistered
+			}
+		}
+	}
+
+	registered, err := s.chgkRepo.RegisterTeam(&models.ChgkGameTeam{GameID: game.ID, TeamID: team.ID})
+	if err != nil {
+		return nil, err
+	}
+	if !registered {
+		return nil, ErrChgkTeamRegistered
+	}
+
+	return s.GetGame(userID, game.ID)
+}
+
+// NextQuestion opens the next question of the packet and starts its minute.
+// Called after the last question has closed, it finishes the game.
+func (s *chgkService) NextQuestion(userID, gameID uint) (*ChgkGameView, error) {
+	game, err := s.findGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.HostID != userID {
+		return nil, ErrNotChgkHost
+	}
+	if game.Status == models.ChgkFinished {
+		return nil, ErrChgkGameFinished
+	}
+	if len(game.Teams) == 0 {
+		return nil, ErrChgkNoTeams
+	}
+
+	now := time.Now()
+	if current := currentChgkQuestion(game); current != nil && now.Before(*current.ClosesAt) {
+		return nil, ErrChgkRoundInProgress
+	}
+
+	if game.CurrentPosition >= len(game.Questions) {
+		if err := s.chgkRepo.Finish(game); err != nil {
+			return nil, err
+		}
+		return s.GetGame(userID, game.ID)
+	}
+
+	position := game.CurrentPosition + 1
+	opened, err := s.chgkRepo.OpenQuestion(game, position, now, now.Add(ChgkDiscussionTime+chgkHandInTime))
+	if err != nil {
+		return nil, err
+	}
+	if !opened {
+		return nil, ErrChgkRoundInProgress
+	}
+
+	return s.GetGame(userID, game.ID)
+}
+
+// SubmitAnswer records the team's only answer to the open question; it is
+// checked right away but the verdict is revealed when the question closes
+func (s *chgkService) SubmitAnswer(userID, gameID uint, answer string) (*ChgkAnswerResult, error) {
+	game, err := s.findGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status == models.ChgkFinished {
+		return nil, ErrChgkGameFinished
+	}
+
+	teamID := captainTeamID(game, userID)
+	if teamID == nil {
+		return nil, ErrChgkNotCaptain
+	}
+
+	now := time.Now()
+	question := currentChgkQuestion(game)
+	if question == nil || now.After(question.ClosesAt.Add(quizGracePeriod)) {
+		return nil, ErrChgkNoOpenQuestion
+	}
+
+	saved, err := s.chgkRepo.SaveAnswer(&models.ChgkAnswer{
+		QuestionID:  question.ID,
+		TeamID:      *teamID,
+		Answer:      answer,
+		Correct:     answerMatches(&question.Riddle, answer),
+		SubmittedBy: userID,
+		SubmittedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrChgkAlreadyAnswered
+	}
+
+	return &ChgkAnswerResult{
+		Position:    question.Position,
+		TeamID:      *teamID,
+		SubmittedAt: now,
+	}, nil
+}
+
+func (s *chgkService) GetResults(gameID uint) (*ChgkResults, error) {
+	game, err := s.findGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := s.chgkRepo.FindAnswers(game.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only closed questions count, so the table never hints at answers still being played
+	now := time.Now()
+	var closed []models.ChgkGameQuestion
+	for _, question := range game.Questions {
+		if question.ClosesAt != nil && now.After(*question.ClosesAt) {
+			closed = append(closed, question)
+		}
+	}
+
+	correct := make(map[uint]map[uint]bool) // question ID -> team ID
+	for _, answer := range answers {
+		if !answer.Correct {
+			continue
+		}
+		if correct[answer.QuestionID] == nil {
+			correct[answer.QuestionID] = make(map[uint]bool)
+		}
+		correct[answer.QuestionID][answer.TeamID] = true
+	}
+
+	rows := make([]ChgkResultRow, 0, len(game.Teams))
+	for _, registered := range game.Teams {
+		row := ChgkResultRow{
+			TeamID:   registered.TeamID,
+			TeamName: registered.Team.Name,
+			Marks:    make([]bool, len(closed)),
+		}
+		for i, question := range closed {
+			if correct[question.ID][registered.TeamID] {
+				row.Marks[i] = true
+				row.Correct++
+				row.Rating += len(game.Teams) - len(correct[question.ID]) + 1
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Correct != rows[j].Correct {
+			return rows[i].Correct > rows[j].Correct
+		}
+		return rows[i].Rating > rows[j].Rating
+	})
+	assignChgkPlaces(rows)
+
+	return &ChgkResults{
+		GameID:    game.ID,
+		Title:     game.Title,
+		Status:    game.Status,
+		Questions: len(closed),
+		Rows:      rows,
+	}, nil
+}
+
+func (s *chgkService) findGame(gameID uint) (*models.ChgkGame, error) {
+	game, err := s.chgkRepo.FindByID(gameID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChgkGameNotFound
+		}
+		return nil, err
+	}
+	return game, nil
+}
+
+func assignChgkPlaces(rows []ChgkResultRow) {
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && rows[end].Correct == rows[start].Correct && rows[end].Rating == rows[start].Rating {
+			end++
+		}
+		place := fmt.Sprint(start + 1)
+		if end-start > 1 {
+			place = fmt.Sprintf("%d-%d", start+1, end)
+		}
+		for i := start; i < end; i++ {
+			rows[i].Place = place
+		}
+		start = end
+	}
+}
+
+func currentChgkQuestion(game *models.ChgkGame) *models.ChgkGameQuestion {
+	if game.CurrentPosition < 1 || game.CurrentPosition > len(game.Questions) {
+		return nil
+	}
+	question := &game.Questions[game.CurrentPosition-1]
+	if question.ClosesAt == nil {
+		return nil
+	}
+	return question
+}
+
+func captainTeamID(game *models.ChgkGame, userID uint) *uint {
+	for _, registered := range game.Teams {
+		if registered.Team.CaptainID == userID {
+			teamID := registered.TeamID
+			return &teamID
+		}
+	}
+	return nil
+}
+
+func newChgkGameView(game *models.ChgkGame, userID uint, answers []models.ChgkAnswer, now time.Time) *ChgkGameView {
+	view := &ChgkGameView{
+		ID:             game.ID,
+		Title:          game.Title,
+		HostID:         game.HostID,
+		Status:         game.Status,
+		TotalQuestions: len(game.Questions),
+		Teams:          make([]ChgkTeamView, 0, len(game.Teams)),
+		MyTeamID:       captainTeamID(game, userID),
+	}
+	for _, registered := range game.Teams {
+		view.Teams = append(view.Teams, ChgkTeamView{ID: registered.TeamID, Name: registered.Team.Name})
+	}
+
+	question := currentChgkQuestion(game)
+	if question == nil || game.Status == models.ChgkFinished {
+		return view
+	}
+
+	closed := now.After(*question.ClosesAt)
+	current := &ChgkQuestionView{
+		Position:         question.Position,
+		Title:            question.Riddle.Title,
+		Description:      question.Riddle.Description,
+		Type:             formatOf(&question.Riddle).Type,
+		Payload:          question.Riddle.Payload,
+		Media:            question.Riddle.Media,
+		OpenedAt:         *question.OpenedAt,
+		DiscussionEndsAt: question.OpenedAt.Add(ChgkDiscussionTime),
+		ClosesAt:         *question.ClosesAt,
+		Closed:           closed,
+	}
+	if !closed {
+		current.RemainingSeconds = int(question.ClosesAt.Sub(now).Seconds())
+	} else {
+		current.CorrectAnswer = SolutionOf(&question.Riddle)
+	}
+	if view.MyTeamID != nil {
+		for _, answer := range answers {
+			if answer.QuestionID == question.ID && answer.TeamID == *view.MyTeamID {
+				current.MyAnswer = answer.Answer
+			}
+		}
+	}
+	view.Current = current
+	return view
+}
\ No newline at end of file
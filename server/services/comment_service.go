@@ -0,0 +1,348 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CommentEditWindow   = 15 * time.Minute
+	CommentDeleteWindow = 24 * time.Hour
+	CommentMaxDepth     = 5 // replies to deeper comments are refused
+	CommentMaxLength    = 2000
+
+	DefaultCommentsPerPage = 20
+	MaxCommentsPerPage     = 50
+)
+
+// commentSpoiler marks both ends of a spoiler block, e.g. "ответ — ||эхо||"
+const commentSpoiler = "||"
+
+var (
+	ErrRiddleNotFound        = errors.New("riddle not found")
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrCommentsLocked        = errors.New("solve or reveal the riddle to see its discussion")
+	ErrCommentInvalid        = errors.New("comment must be 1-2000 characters")
+	ErrCommentTooDeep        = errors.New("thread is nested too deep to reply")
+	ErrCommentNotAuthor      = errors.New("you can only change your own comments")
+	ErrCommentGone           = errors.New("comment has been deleted")
+	ErrCommentEditClosed     = errors.New("comments can only be edited within 15 minutes")
+	ErrCommentDeleteClosed   = errors.New("comments can only be deleted within 24 hours")
+	ErrRemovalReasonRequired = errors.New("a removal reason is required")
+)
+
+type CommentService interface {
+	GetComments(userID, riddleID uint, page, perPage int) (*CommentPage, error)
+	AddComment(userID, riddleID uint, parentID *uint, body string) (*CommentView, error)
+	EditComment(userID, commentID uint, body string) (*CommentView, error)
+	DeleteComment(userID, commentID uint) error
+	RemoveComment(moderatorID, commentID uint, reason string) error
+}
+
+// CommentBlock is a piece of a comment body; spoiler blocks are hidden by the client until clicked
+type CommentBlock struct {
+	Text    string `json:"text"`
+	Spoiler bool   `json:"spoiler,omitempty"`
+}
+
+type CommentView struct {
+	ID         uint           `json:"id"`
+	ParentID   *uint          `json:"parent_id"`
+	AuthorID   uint           `json:"author_id"`
+	AuthorName string         `json:"author_name"`
+	Blocks     []CommentBlock `json:"blocks"` // empty for deleted and removed comments
+	Deleted    bool           `json:"deleted,omitempty"`
+	Removed    bool           `json:"removed,omitempty"`
+	CanEdit    bool           `json:"can_edit"`
+	CanDelete  bool           `json:"can_delete"`
+	CreatedAt  time.Time      `json:"created_at"`
+	EditedAt   *time.Time     `json:"edited_at"`
+	Replies    []*CommentView `json:"replies"`
+}
+
+// CommentPage holds a page of top-level comments with all of their replies
+type CommentPage struct {
+	Page     int            `json:"page"`
+	PerPage  int            `json:"per_page"`
+	Total    int            `json:"total"` // top-level comments
+	Comments []*CommentView `json:"comments"`
+}
+
+type commentService struct {
+	commentRepo  repository.CommentRepository
+	riddleRepo   repository.RiddleRepository
+	progressRepo repository.ProgressRepository
+	userRepo     repository.UserRepository
+}
+
+func NewCommentService(
+	commentRepo repository.CommentRepository,
+	riddleRepo repository.RiddleRepository,
+	progressRepo repository.ProgressRepository,
+	userRepo repository.UserRepository,
+) CommentService {
+	return &commentService{
+		commentRepo:  commentRepo,
+		riddleRepo:   riddleRepo,
+		progressRepo: progressRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func (s *commentService) GetComments(userID, riddleID uint, page, perPage int) (*CommentPage, error) {
+	if err := s.checkAccess(userID, riddleID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultCommentsPerPage
+	}
+	if perPage > MaxCommentsPerPage {
+		perPage = MaxCommentsPerPage
+	}
+
+	roots, total, err := s.commentRepo.FindRoots(riddleID, (page-1)*perPage, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	rootIDs := make([]uint, len(roots))
+	for i, root := range roots {
+		rootIDs[i] = root.ID
+	}
+	replies, err := s.commentRepo.FindReplies(rootIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replies come oldest first, so every parent is seen before its children
+	now := time.Now()
+	views := make(map[uint]*CommentView, len(roots)+len(replies))
+	result := &CommentPage{Page: page, PerPage: perPage, Total: total, Comments: make([]*CommentView, len(roots))}
+	for i := range roots {
+		view := newCommentView(&roots[i], userID, now)
+		views[view.ID] = view
+		result.Comments[i] = view
+	}
+	for i := range replies {
+		view := newCommentView(&replies[i], userID, now)
+		views[view.ID] = view
+		if parent, ok := views[*view.ParentID]; ok {
+			parent.Replies = append(parent.Replies, view)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *commentService) AddComment(userID, riddleID uint, parentID *uint, body string) (*CommentView, error) {
+	body, err := validCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAccess(userID, riddleID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		RiddleID: riddleID,
+		UserID:   userID,
+		Body:     body,
+	}
+
+	if parentID != nil {
+		parent, err := s.findComment(*parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.RiddleID != riddleID {
+			return nil, ErrCommentNotFound
+		}
+		if parent.Depth >= CommentMaxDepth {
+			return nil, ErrCommentTooDeep
+		}
+
+		comment.ParentID = &parent.ID
+		comment.RootID = parent.RootID
+		if comment.RootID == nil {
+			comment.RootID = &parent.ID
+		}
+		comment.Depth = parent.Depth + 1
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	created, err := s.commentRepo.FindByID(comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newCommentView(created, userID, time.Now()), nil
+}
+
+func (s *commentService) EditComment(userID, commentID uint, body string) (*CommentView, error) {
+	body, err := validCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.findOwnComment(userID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Sub(comment.CreatedAt) > CommentEditWindow {
+		return nil, ErrCommentEditClosed
+	}
+
+	if err := s.commentRepo.UpdateBody(comment.ID, body, now); err != nil {
+		return nil, err
+	}
+
+	comment.Body = body
+	comment.EditedAt = &now
+	return newCommentView(comment, userID, now), nil
+}
+
+func (s *commentService) DeleteComment(userID, commentID uint) error {
+	comment, err := s.findOwnComment(userID, commentID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Sub(comment.CreatedAt) > CommentDeleteWindow {
+		return ErrCommentDeleteClosed
+	}
+	return s.commentRepo.MarkDeleted(comment.ID, now)
+}
+
+func (s *commentService) RemoveComment(moderatorID, commentID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrRemovalReasonRequired
+	}
+
+	comment, err := s.findComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.RemovedAt != nil {
+		return ErrCommentGone
+	}
+	return s.commentRepo.MarkRemoved(comment.ID, moderatorID, reason, time.Now())
+}
+
+// checkAccess lets through players who solved or revealed the riddle, and moderators
+func (s *commentService) checkAccess(userID, riddleID uint) error {
+	if _, err := s.riddleRepo.FindPublishedByID(riddleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRiddleNotFound
+		}
+		return err
+	}
+
+	progress, err := s.progressRepo.FindByUserAndRiddle(userID, riddleID)
+	if err == nil && (progress.Solved || progress.Revealed) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == models.RoleModerator || user.Role == models.RoleAdmin {
+		return nil
+	}
+	return ErrCommentsLocked
+}
+
+func (s *commentService) findComment(commentID uint) (*models.Comment, error) {
+	comment, err := s.commentRepo.FindByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) findOwnComment(userID, commentID uint) (*models.Comment, error) {
+	comment, err := s.findComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrCommentNotAuthor
+	}
+	if comment.DeletedAt != nil || comment.RemovedAt != nil {
+		return nil, ErrCommentGone
+	}
+	return comment, nil
+}
+
+func newCommentView(comment *models.Comment, viewerID uint, now time.Time) *CommentView {
+	view := &CommentView{
+		ID:         comment.ID,
+		ParentID:   comment.ParentID,
+		AuthorID:   comment.UserID,
+		AuthorName: comment.User.Username,
+		Blocks:     []CommentBlock{},
+		Deleted:    comment.DeletedAt != nil,
+		Removed:    comment.RemovedAt != nil,
+		CreatedAt:  comment.CreatedAt,
+		EditedAt:   comment.EditedAt,
+		Replies:    []*CommentView{},
+	}
+	if view.Deleted || view.Removed {
+		return view
+	}
+
+	view.Blocks = parseCommentBlocks(comment.Body)
+	if comment.UserID == viewerID {
+		view.CanEdit = now.Sub(comment.CreatedAt) <= CommentEditWindow
+		view.CanDelete = now.Sub(comment.CreatedAt) <= CommentDeleteWindow
+	}
+	return view
+}
+
+// parseCommentBlocks splits a body on spoiler markers. An unclosed marker is
+// treated as plain text so a stray "||" doesn't hide the rest of the comment.
+func parseCommentBlocks(body string) []CommentBlock {
+	parts := strings.Split(body, commentSpoiler)
+	if len(parts)%2 == 0 {
+		last := len(parts) - 2
+		parts = append(parts[:last], parts[last]+commentSpoiler+parts[last+1])
+	}
+
+	blocks := make([]CommentBlock, 0, len(parts))
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		blocks = append(blocks, CommentBlock{Text: part, Spoiler: i%2 == 1})
+	}
+	return blocks
+}
+
+func validCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || len([]rune(body)) > CommentMaxLength {
+		return "", ErrCommentInvalid
+	}
+	return body, nil
+}
\ No newline at end of file
@@ -7,29 +7,65 @@ import (
 )
 
 type DailyRiddleService interface {
-	GetTodayRiddle() (*models.DailyRiddle, error)
-	GetRiddleByDate(date time.Time) (*models.DailyRiddle, error)
+	GetTodayRiddle() (*DailyRiddleView, error)
+	GetRiddleByDate(date time.Time) (*DailyRiddleView, error)
+	GetRiddlesByDate(date time.Time) ([]models.DailyRiddle, error)
 	GetRiddlesForDateRange(startDate, endDate time.Time) ([]models.DailyRiddle, error)
 }
 
+// DailyRiddleView is a featured riddle as players see it; answers are
+// published separately once the day is over
+type DailyRiddleView struct {
+	ID           uint         `json:"id"`
+	RiddleID     uint         `json:"riddle_id"`
+	Riddle       PlayerRiddle `json:"riddle"`
+	FeaturedDate time.Time    `json:"featured_date"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
 type dailyRiddleService struct {
 	dailyRiddleRepo repository.DailyRiddleRepository
+	mediaService    MediaService
 }
 
-func NewDailyRiddleService(dailyRiddleRepo repository.DailyRiddleRepository) DailyRiddleService {
+func NewDailyRiddleService(dailyRiddleRepo repository.DailyRiddleRepository, mediaService MediaService) DailyRiddleService {
 	return &dailyRiddleService{
 		dailyRiddleRepo: dailyRiddleRepo,
+		mediaService:    mediaService,
 	}
 }
 
-func (s *dailyRiddleService) GetTodayRiddle() (*models.DailyRiddle, error) {
-	return s.dailyRiddleRepo.FindToday()
+func (s *dailyRiddleService) GetTodayRiddle() (*DailyRiddleView, error) {
+	dailyRiddle, err := s.dailyRiddleRepo.FindToday()
+	if err != nil {
+		return nil, err
+	}
+	return s.view(dailyRiddle), nil
 }
 
-func (s *dailyRiddleService) GetRiddleByDate(date time.Time) (*models.DailyRiddle, error) {
-	return s.dailyRiddleRepo.FindByDate(date)
+func (s *dailyRiddleService) GetRiddleByDate(date time.Time) (*DailyRiddleView, error) {
+	dailyRiddle, err := s.dailyRiddleRepo.FindByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(dailyRiddle), nil
+}
+
+func (s *dailyRiddleService) GetRiddlesByDate(date time.Time) ([]models.DailyRiddle, error) {
+	return s.dailyRiddleRepo.FindAllByDate(date)
 }
 
 func (s *dailyRiddleService) GetRiddlesForDateRange(startDate, endDate time.Time) ([]models.DailyRiddle, error) {
 	return s.dailyRiddleRepo.GetRiddlesForDateRange(startDate, endDate)
+}
+
+func (s *dailyRiddleService) view(dailyRiddle *models.DailyRiddle) *DailyRiddleView {
+	s.mediaService.Sign(dailyRiddle.Riddle.Media)
+	return &DailyRiddleView{
+		ID:           dailyRiddle.ID,
+		RiddleID:     dailyRiddle.RiddleID,
+		Riddle:       NewPlayerRiddle(&dailyRiddle.Riddle),
+		FeaturedDate: dailyRiddle.FeaturedDate,
+		CreatedAt:    dailyRiddle.CreatedAt,
+	}
 }
\ No newline at end of file
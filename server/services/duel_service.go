@@ -0,0 +1,586 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDuelRiddles = 5
+	maxDuelRiddles     = 10
+	// DuelDuration is how long both sides have to accept and play a duel
+	DuelDuration = 72 * time.Hour
+	// DuelMaxAttempts is how many answers a riddle takes before it counts as failed
+	DuelMaxAttempts = 3
+)
+
+var (
+	ErrDuelNotFound        = errors.New("duel not found")
+	ErrDuelOpponentInvalid = errors.New("opponent not found")
+	ErrDuelSelfChallenge   = errors.New("you cannot challenge yourself")
+	ErrDuelNotPending      = errors.New("duel is no longer pending")
+	ErrDuelNotOpponent     = errors.New("only the challenged player can answer the challenge")
+	ErrDuelOver            = errors.New("duel is over")
+	ErrDuelNotAccepted     = errors.New("duel has not been accepted yet")
+	ErrDuelAlreadyPlayed   = errors.New("you have already played this duel")
+	ErrDuelNotStarted      = errors.New("duel has not been started")
+	ErrDuelAlreadyAnswered = errors.New("riddle already answered")
+)
+
+type DuelService interface {
+	CreateDuel(userID uint, options DuelOptions) (*DuelView, error)
+	GetDuel(userID, duelID uint) (*DuelView, error)
+	ListDuels(userID uint, status string) ([]DuelView, error)
+	AcceptDuel(userID, duelID uint) (*DuelView, error)
+	DeclineDuel(userID, duelID uint) (*DuelView, error)
+	StartDuel(userID, duelID uint) (*DuelPlayState, error)
+	SubmitAnswer(userID, duelID uint, answer string) (*DuelAnswerResult, error)
+	SkipRiddle(userID, duelID uint) (*DuelAnswerResult, error)
+}
+
+type DuelOptions struct {
+	OpponentID uint   `json:"opponent_id"`
+	Riddles    int    `json:"riddles"`
+	CategoryID uint   `json:"category_id"`
+	Difficulty string `json:"difficulty"`
+}
+
+type DuelView struct {
+	ID         uint               `json:"id"`
+	Status     string             `json:"status"`
+	Riddles    int                `json:"riddles"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	WinnerID   *uint              `json:"winner_id"`
+	Challenger DuelSideView       `json:"challenger"`
+	Opponent   DuelSideView       `json:"opponent"`
+	Results    []DuelRiddleResult `json:"results,omitempty"` // once the duel is finished
+}
+
+type DuelSideView struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Started  bool   `json:"started"`
+	Finished bool   `json:"finished"`
+	Solved   int    `json:"solved"`
+	Attempts int    `json:"attempts"`
+	TimeMs   int64  `json:"time_ms"` // spent on solved riddles
+}
+
+type DuelRiddleResult struct {
+	Position      int             `json:"position"`
+	RiddleID      uint            `json:"riddle_id"`
+	Title         string          `json:"title"`
+	CorrectAnswer string          `json:"correct_answer"`
+	Challenger    *DuelAnswerView `json:"challenger"`
+	Opponent      *DuelAnswerView `json:"opponent"`
+}
+
+type DuelAnswerView struct {
+	Solved     bool  `json:"solved"`
+	Attempts   int   `json:"attempts"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// DuelPlayState is the player's position in their run; riddles are shown one at a time
+type DuelPlayState struct {
+	DuelID   uint               `json:"duel_id"`
+	Total    int                `json:"total"`
+	Finished bool               `json:"finished"`
+	Current  *DuelCurrentRiddle `json:"current,omitempty"`
+}
+
+type DuelCurrentRiddle struct {
+	Position     int                  `json:"position"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	Category     string               `json:"category"`
+	Difficulty   string               `json:"difficulty"`
+	Type         string               `json:"type"`
+	Payload      models.RiddlePayload `json:"payload"`
+	Media        []models.RiddleMedia `json:"media,omitempty"`
+	ShownAt      time.Time            `json:"shown_at"`
+	AttemptsLeft int                  `json:"attempts_left"`
+}
+
+type DuelAnswerResult struct {
+	Position     int            `json:"position"`
+	Correct      bool           `json:"correct"`
+	AttemptsLeft int            `json:"attempts_left"`
+	Play         *DuelPlayState `json:"play"`
+}
+
+type duelService struct {
+	duelRepo     repository.DuelRepository
+	userRepo     repository.UserRepository
+	riddleRepo   repository.RiddleRepository
+	mediaService MediaService
+}
+
+// Like quizzes, duels are a separate game mode and don't touch riddle progress or points
+func NewDuelService(duelRepo repository.DuelRepository, userRepo repository.UserRepository, riddleRepo repository.RiddleRepository, mediaService MediaService) DuelService {
+	return &duelService{
+		duelRepo:     duelRepo,
+		userRepo:     userRepo,
+		riddleRepo:   riddleRepo,
+		mediaService: mediaService,
+	}
+}
+
+func (s *duelService) CreateDuel(userID uint, options DuelOptions) (*DuelView, error) {
+	if options.OpponentID == userID {
+		return nil, ErrDuelSelfChallenge
+	}
+	if _, err := s.userRepo.FindByID(options.OpponentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDuelOpponentInvalid
+		}
+		return nil, err
+	}
+
+	count := options.Riddles
+	if count <= 0 {
+		count = defaultDuelRiddles
+	}
+	if count > maxDuelRiddles {
+		count = maxDuelRiddles
+	}
+
+	riddles, err := s.riddleRepo.FindRandom(options.CategoryID, options.Difficulty, count)
+	if err != nil {
+		return nil, err
+	}
+	if len(riddles) == 0 {
+		return nil, ErrNotEnoughRiddles
+	}
+
+	duel := &models.Duel{
+		ChallengerID: userID,
+		OpponentID:   options.OpponentID,
+		Status:       models.DuelPending,
+		ExpiresAt:    time.Now().Add(DuelDuration),
+	}
+	for i, riddle := range riddles {
+		duel.Riddles = append(duel.Riddles, models.DuelRiddle{
+			Position: i + 1,
+			RiddleID: riddle.ID,
+		})
+	}
+
+	if err := s.duelRepo.Create(duel); err != nil {
+		return nil, err
+	}
+
+	return s.GetDuel(userID, duel.ID)
+}
+
+func (s *duelService) GetDuel(userID, duelID uint) (*DuelView, error) {
+	duel, err := s.findDuel(userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+	return newDuelView(duel), nil
+}
+
+func (s *duelService) ListDuels(userID uint, status string) ([]DuelView, error) {
+	duels, err := s.duelRepo.FindByUserID(userID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	views := make([]DuelView, 0, len(duels))
+	for i := range duels {
+		if err := s.resolve(&duels[i], now); err != nil {
+			return nil, err
+		}
+		// Resolving may have moved the duel out of the requested status
+		if status != "" && duels[i].Status != status {
+			continue
+		}
+		views = append(views, *newDuelView(&duels[i]))
+	}
+	return views, nil
+}
+
+func (s *duelService) AcceptDuel(userID, duelID uint) (*DuelView, error) {
+	return s.answerChallenge(userID, duelID, models.DuelAccepted)
+}
+
+func (s *duelService) DeclineDuel(userID, duelID uint) (*DuelView, error) {
+	return s.answerChallenge(userID, duelID, models.DuelDeclined)
+}
+
+func (s *duelService) StartDuel(userID, duelID uint) (*DuelPlayState, error) {
+	duel, err := s.findPlayableDuel(userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if play := findDuelPlay(duel, userID); play != nil {
+		if play.FinishedAt != nil {
+			return nil, ErrDuelAlreadyPlayed
+		}
+		// Starting again resumes the run where it was left
+		return s.playState(duel, play), nil
+	}
+
+	now := time.Now()
+	play := &models.DuelPlay{
+		DuelID:    duel.ID,
+		UserID:    userID,
+		StartedAt: now,
+		Answers:   []models.DuelAnswer{{Position: 1, ShownAt: now}},
+	}
+	started, err := s.duelRepo.StartPlay(play)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, ErrDuelAlreadyPlayed
+	}
+
+	duel.Plays = append(duel.Plays, *play)
+	return s.playState(duel, play), nil
+}
+
+func (s *duelService) SubmitAnswer(userID, duelID uint, answer string) (*DuelAnswerResult, error) {
+	duel, play, current, err := s.findCurrentRiddle(userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+
+	riddle := duel.Riddles[current.Position-1].Riddle
+	correct := answerMatches(&riddle, answer)
+
+	recorded, err := s.duelRepo.RecordAttempt(current.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, ErrDuelAlreadyAnswered
+	}
+
+	attempts := current.Attempts + 1
+	result := &DuelAnswerResult{
+		Position:     current.Position,
+		Correct:      correct,
+		AttemptsLeft: DuelMaxAttempts - attempts,
+	}
+	if !correct && attempts < DuelMaxAttempts {
+		current.Attempts = attempts
+		result.Play = s.playState(duel, play)
+		return result, nil
+	}
+
+	result.AttemptsLeft = 0
+	if result.Play, err = s.closeRiddle(userID, duel, play, current, correct); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SkipRiddle gives up the current riddle, which then counts as failed
+func (s *duelService) SkipRiddle(userID, duelID uint) (*DuelAnswerResult, error) {
+	duel, play, current, err := s.findCurrentRiddle(userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.closeRiddle(userID, duel, play, current, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DuelAnswerResult{
+		Position: current.Position,
+		Play:     state,
+	}, nil
+}
+
+func (s *duelService) answerChallenge(userID, duelID uint, status string) (*DuelView, error) {
+	duel, err := s.findDuel(userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel.OpponentID != userID {
+		return nil, ErrDuelNotOpponent
+	}
+	if duel.Status != models.DuelPending {
+		return nil, ErrDuelNotPending
+	}
+
+	duel.Status = status
+	updated, err := s.duelRepo.UpdateStatus(duel, []string{models.DuelPending})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrDuelNotPending
+	}
+
+	return newDuelView(duel), nil
+}
+
+// closeRiddle records the riddle's outcome and shows the next one, finishing
+// the run (and possibly the duel) after the last riddle
+func (s *duelService) closeRiddle(userID uint, duel *models.Duel, play *models.DuelPlay, current *models.DuelAnswer, solved bool) (*DuelPlayState, error) {
+	now := time.Now()
+	closed, err := s.duelRepo.CloseAnswer(current.ID, solved, now, now.Sub(current.ShownAt).Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrDuelAlreadyAnswered
+	}
+
+	if current.Position < len(duel.Riddles) {
+		next := &models.DuelAnswer{PlayID: play.ID, Position: current.Position + 1, ShownAt: now}
+		if err := s.duelRepo.ShowRiddle(next); err != nil {
+			return nil, err
+		}
+	} else if err := s.duelRepo.FinishPlay(play.ID, now); err != nil {
+		return nil, err
+	}
+
+	// Reload so the duel is resolved once both sides are done
+	duel, err = s.findDuel(userID, duel.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.playState(duel, findDuelPlay(duel, userID)), nil
+}
+
+func (s *duelService) findDuel(userID, duelID uint) (*models.Duel, error) {
+	duel, err := s.duelRepo.FindByID(duelID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && duel.ChallengerID != userID && duel.OpponentID != userID) {
+		return nil, ErrDuelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolve(duel, time.Now()); err != nil {
+		return nil, err
+	}
+	return duel, nil
+}
+
+// findPlayableDuel allows the challenger to play right away and the opponent once they accepted
+func (s *duelService) findPlayableDuel(userID, duelID uint) (*models.Duel, error) {
+	duel, err := s.findDuel(userID, duelID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch duel.Status {
+	case models.DuelAccepted:
+	case models.DuelPending:
+		if userID == duel.OpponentID {
+			return nil, ErrDuelNotAccepted
+		}
+	default:
+		return nil, ErrDuelOver
+	}
+	return duel, nil
+}
+
+func (s *duelService) findCurrentRiddle(userID, duelID uint) (*models.Duel, *models.DuelPlay, *models.DuelAnswer, error) {
+	duel, err := s.findPlayableDuel(userID, duelID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	play := findDuelPlay(duel, userID)
+	if play == nil {
+		return nil, nil, nil, ErrDuelNotStarted
+	}
+	if play.FinishedAt != nil {
+		return nil, nil, nil, ErrDuelAlreadyPlayed
+	}
+
+	current := currentDuelAnswer(play)
+	if current == nil {
+		return nil, nil, nil, ErrDuelAlreadyAnswered
+	}
+	return duel, play, current, nil
+}
+
+// resolve finishes a duel once both sides played or time ran out. An expired
+// duel that was accepted is judged on what each side managed to solve.
+func (s *duelService) resolve(duel *models.Duel, now time.Time) error {
+	if duel.Status != models.DuelPending && duel.Status != models.DuelAccepted {
+		return nil
+	}
+
+	expired := now.After(duel.ExpiresAt)
+	challenger := findDuelPlay(duel, duel.ChallengerID)
+	opponent := findDuelPlay(duel, duel.OpponentID)
+	bothFinished := challenger != nil && challenger.FinishedAt != nil && opponent != nil && opponent.FinishedAt != nil
+
+	switch {
+	case duel.Status == models.DuelPending && expired:
+		duel.Status = models.DuelExpired
+	case duel.Status == models.DuelAccepted && (bothFinished || expired):
+		duel.Status = models.DuelFinished
+		duel.WinnerID = duelWinner(duel)
+	default:
+		return nil
+	}
+
+	_, err := s.duelRepo.UpdateStatus(duel, []string{models.DuelPending, models.DuelAccepted})
+	return err
+}
+
+// duelWinner compares solved riddles, then time spent on them, then attempts
+func duelWinner(duel *models.Duel) *uint {
+	challenger := newDuelSideView(findDuelPlay(duel, duel.ChallengerID))
+	opponent := newDuelSideView(findDuelPlay(duel, duel.OpponentID))
+
+	var diff int64
+	switch {
+	case challenger.Solved != opponent.Solved:
+		diff = int64(opponent.Solved - challenger.Solved)
+	case challenger.TimeMs != opponent.TimeMs:
+		diff = challenger.TimeMs - opponent.TimeMs
+	default:
+		diff = int64(challenger.Attempts - opponent.Attempts)
+	}
+
+	switch {
+	case diff < 0:
+		return &duel.ChallengerID
+	case diff > 0:
+		return &duel.OpponentID
+	}
+	return nil
+}
+
+func findDuelPlay(duel *models.Duel, userID uint) *models.DuelPlay {
+	for i := range duel.Plays {
+		if duel.Plays[i].UserID == userID {
+			return &duel.Plays[i]
+		}
+	}
+	return nil
+}
+
+func currentDuelAnswer(play *models.DuelPlay) *models.DuelAnswer {
+	for i := range play.Answers {
+		if play.Answers[i].AnsweredAt == nil {
+			return &play.Answers[i]
+		}
+	}
+	return nil
+}
+
+// playState signs the media of the current riddle after building the state
+func (s *duelService) playState(duel *models.Duel, play *models.DuelPlay) *DuelPlayState {
+	state := newDuelPlayState(duel, play)
+	if state.Current != nil {
+		s.mediaService.Sign(state.Current.Media)
+	}
+	return state
+}
+
+func newDuelPlayState(duel *models.Duel, play *models.DuelPlay) *DuelPlayState {
+	state := &DuelPlayState{
+		DuelID:   duel.ID,
+		Total:    len(duel.Riddles),
+		Finished: play.FinishedAt != nil,
+	}
+
+	current := currentDuelAnswer(play)
+	if current == nil || state.Finished {
+		return state
+	}
+
+	riddle := duel.Riddles[current.Position-1].Riddle
+	state.Current = &DuelCurrentRiddle{
+		Position:     current.Position,
+		Title:        riddle.Title,
+		Description:  riddle.Description,
+		Category:     riddle.Category.Name,
+		Difficulty:   riddle.DisplayedDifficulty(),
+		Type:         formatOf(&riddle).Type,
+		Payload:      riddle.Payload,
+		Media:        riddle.Media,
+		ShownAt:      current.ShownAt,
+		AttemptsLeft: DuelMaxAttempts - current.Attempts,
+	}
+	return state
+}
+
+func newDuelSideView(play *models.DuelPlay) DuelSideView {
+	var side DuelSideView
+	if play == nil {
+		return side
+	}
+
+	side.Started = true
+	side.Finished = play.FinishedAt != nil
+	for _, answer := range play.Answers {
+		side.Attempts += answer.Attempts
+		if answer.Solved {
+			side.Solved++
+			side.TimeMs += answer.DurationMs
+		}
+	}
+	return side
+}
+
+func newDuelAnswerView(play *models.DuelPlay, position int) *DuelAnswerView {
+	if play == nil {
+		return nil
+	}
+	for _, answer := range play.Answers {
+		if answer.Position == position && answer.AnsweredAt != nil {
+			return &DuelAnswerView{
+				Solved:     answer.Solved,
+				Attempts:   answer.Attempts,
+				DurationMs: answer.DurationMs,
+			}
+		}
+	}
+	return nil
+}
+
+func newDuelView(duel *models.Duel) *DuelView {
+	challengerPlay := findDuelPlay(duel, duel.ChallengerID)
+	opponentPlay := findDuelPlay(duel, duel.OpponentID)
+
+	view := &DuelView{
+		ID:         duel.ID,
+		Status:     duel.Status,
+		Riddles:    len(duel.Riddles),
+		ExpiresAt:  duel.ExpiresAt,
+		CreatedAt:  duel.CreatedAt,
+		WinnerID:   duel.WinnerID,
+		Challenger: newDuelSideView(challengerPlay),
+		Opponent:   newDuelSideView(opponentPlay),
+	}
+	view.Challenger.UserID = duel.ChallengerID
+	view.Challenger.Username = duel.Challenger.Username
+	view.Opponent.UserID = duel.OpponentID
+	view.Opponent.Username = duel.Opponent.Username
+
+	// Riddles and answers stay hidden until nobody can play anymore
+	if duel.Status != models.DuelFinished {
+		return view
+	}
+	for _, duelRiddle := range duel.Riddles {
+		view.Results = append(view.Results, DuelRiddleResult{
+			Position:      duelRiddle.Position,
+			RiddleID:      duelRiddle.RiddleID,
+			Title:         duelRiddle.Riddle.Title,
+			CorrectAnswer: SolutionOf(&duelRiddle.Riddle),
+			Challenger:    newDuelAnswerView(challengerPlay, duelRiddle.Position),
+			Opponent:      newDuelAnswerView(opponentPlay, duelRiddle.Position),
+		})
+	}
+	return view
+}
\ No newline at end of file
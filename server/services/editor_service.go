@@ -0,0 +1,463 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRiddleVersionConflict = errors.New("riddle was changed by someone else; reload it and apply your edit again")
+	ErrRevisionNotFound      = errors.New("revision not found")
+	ErrRiddleStatusInvalid   = errors.New("status must be draft, scheduled, published or archived")
+	ErrPublishAtRequired     = errors.New("scheduled riddles need a publish_at in the future")
+)
+
+// EditorService creates and edits riddles, keeping a revision for every change
+type EditorService interface {
+	CreateRiddle(editorID uint, edit RiddleEdit) (*models.Riddle, error)
+	UpdateRiddle(editorID, riddleID uint, edit RiddleEdit) (*models.Riddle, error)
+	GetHistory(riddleID uint) ([]RevisionView, error)
+	Rollback(editorID, riddleID uint, version, expectedVersion int) (*models.Riddle, error)
+	ListRiddles(status string) ([]models.Riddle, error) // every status when empty
+	SetStatus(editorID, riddleID uint, change StatusChange) (*models.Riddle, error)
+	DeleteRiddle(riddleID uint) error
+	PublishDue() error
+}
+
+// RiddleEdit replaces a riddle's content. Version is the version the edit
+// was based on; it must still be current for the edit to apply.
+type RiddleEdit struct {
+	RiddleInput
+	RiddleFormat
+	AcceptedAnswers []string `json:"accepted_answers"`
+	Version         int      `json:"version"`
+}
+
+// StatusChange moves a riddle through its lifecycle; PublishAt is required
+// for scheduled riddles and ignored otherwise
+type StatusChange struct {
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+	Version   int        `json:"version"`
+}
+
+// RiddleSnapshot is the editable content of a riddle as stored in revisions.
+// Revisions recorded before riddles had a type or a status have them empty.
+type RiddleSnapshot struct {
+	Title           string               `json:"title"`
+	Description     string               `json:"description"`
+	Answer          string               `json:"answer"`
+	AcceptedAnswers []string             `json:"accepted_answers"`
+	Explanation     string               `json:"explanation,omitempty"`
+	CategoryID      uint                 `json:"category_id"`
+	Difficulty      string               `json:"difficulty"`
+	Type            string               `json:"type,omitempty"`
+	Payload         models.RiddlePayload `json:"payload"`
+	Key             models.RiddleKey     `json:"key"`
+	Status          string               `json:"status,omitempty"`
+	PublishAt       *time.Time           `json:"publish_at,omitempty"`
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type RevisionView struct {
+	Version    int            `json:"version"`
+	Action     string         `json:"action"`
+	AuthorID   *uint          `json:"author_id"`
+	AuthorName string         `json:"author_name,omitempty"`
+	RestoredTo *int           `json:"restored_to,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Snapshot   RiddleSnapshot `json:"snapshot"`
+	Changes    []FieldChange  `json:"changes"`
+}
+
+type editorService struct {
+	riddleRepo   repository.RiddleRepository
+	revisionRepo repository.RevisionRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewEditorService(
+	riddleRepo repository.RiddleRepository,
+	revisionRepo repository.RevisionRepository,
+	categoryRepo repository.CategoryRepository,
+) EditorService {
+	return &editorService{
+		riddleRepo:   riddleRepo,
+		revisionRepo: revisionRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *editorService) CreateRiddle(editorID uint, edit RiddleEdit) (*models.Riddle, error) {
+	snapshot, err := s.contentOf(edit)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Status = models.RiddleDraft
+
+	riddle := &models.Riddle{Version: 1}
+	snapshot.applyTo(riddle)
+
+	revision, err := newRevision(editorID, models.RevisionCreate, 1, RiddleSnapshot{}, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.riddleRepo.CreateWithRevision(riddle, revision); err != nil {
+		return nil, err
+	}
+	return s.riddleRepo.FindByID(riddle.ID)
+}
+
+func (s *editorService) UpdateRiddle(editorID, riddleID uint, edit RiddleEdit) (*models.Riddle, error) {
+	content, err := s.contentOf(edit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commit(editorID, riddleID, edit.Version, models.RevisionEdit, nil, func(snapshot *RiddleSnapshot) {
+		snapshot.setContent(content)
+	})
+}
+
+// contentOf validates the edit and returns the content it sets
+func (s *editorService) contentOf(edit RiddleEdit) (RiddleSnapshot, error) {
+	input := edit.trimmed()
+	format := edit.RiddleFormat.normalized()
+	if err := validateRiddle(s.categoryRepo, input, format); err != nil {
+		return RiddleSnapshot{}, err
+	}
+
+	accepted := cleanAcceptedAnswers(edit.AcceptedAnswers)
+	if len(accepted) > 0 && format.Type != models.RiddleText {
+		return RiddleSnapshot{}, fmt.Errorf("%w: only text riddles have accepted answers", ErrRiddleInvalid)
+	}
+
+	return RiddleSnapshot{
+		Title:           input.Title,
+		Description:     input.Description,
+		Answer:          input.Answer,
+		AcceptedAnswers: accepted,
+		Explanation:     input.Explanation,
+		CategoryID:      input.CategoryID,
+		Difficulty:      input.Difficulty,
+		Type:            format.Type,
+		Payload:         format.Payload,
+		Key:             format.Key,
+	}, nil
+}
+
+func (s *editorService) GetHistory(riddleID uint) ([]RevisionView, error) {
+	if _, err := s.findRiddle(riddleID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.FindByRiddle(riddleID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]RevisionView, len(revisions))
+	for i, revision := range revisions {
+		view := RevisionView{
+			Version:    revision.Version,
+			Action:     revision.Action,
+			AuthorID:   revision.AuthorID,
+			RestoredTo: revision.RestoredTo,
+			CreatedAt:  revision.CreatedAt,
+			Changes:    []FieldChange{},
+		}
+		if revision.Author != nil {
+			view.AuthorName = revision.Author.Username
+		}
+		if err := json.Unmarshal([]byte(revision.Snapshot), &view.Snapshot); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(revision.Diff), &view.Changes); err != nil {
+			return nil, err
+		}
+		views[i] = view
+	}
+	return views, nil
+}
+
+// Rollback restores the content of an earlier version as a new revision
+func (s *editorService) Rollback(editorID, riddleID uint, version, expectedVersion int) (*models.Riddle, error) {
+	revision, err := s.revisionRepo.FindByVersion(riddleID, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	var snapshot RiddleSnapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Type == "" {
+		snapshot.Type = models.RiddleText
+	}
+
+	// The category may have been removed since
+	if _, err := s.categoryRepo.FindByID(snapshot.CategoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	// Only the content is restored; the riddle stays in its current status
+	return s.commit(editorID, riddleID, expectedVersion, models.RevisionRollback, &version, func(current *RiddleSnapshot) {
+		current.setContent(snapshot)
+	})
+}
+
+func (s *editorService) ListRiddles(status string) ([]models.Riddle, error) {
+	if status != "" && !validRiddleStatus(status) {
+		return nil, ErrRiddleStatusInvalid
+	}
+	return s.riddleRepo.FindForEditors(status)
+}
+
+func (s *editorService) SetStatus(editorID, riddleID uint, change StatusChange) (*models.Riddle, error) {
+	if !validRiddleStatus(change.Status) {
+		return nil, ErrRiddleStatusInvalid
+	}
+
+	var publishAt *time.Time
+	if change.Status == models.RiddleScheduled {
+		if change.PublishAt == nil || !change.PublishAt.After(time.Now()) {
+			return nil, ErrPublishAtRequired
+		}
+		at := change.PublishAt.UTC()
+		publishAt = &at
+	}
+
+	return s.commit(editorID, riddleID, change.Version, models.RevisionStatus, nil, func(snapshot *RiddleSnapshot) {
+		snapshot.Status = change.Status
+		snapshot.PublishAt = publishAt
+	})
+}
+
+// DeleteRiddle hides the riddle for good; players keep their progress,
+// favorites and ratings of it
+func (s *editorService) DeleteRiddle(riddleID uint) error {
+	if _, err := s.findRiddle(riddleID); err != nil {
+		return err
+	}
+	return s.riddleRepo.SoftDelete(riddleID)
+}
+
+// PublishDue publishes the scheduled riddles whose time has come. A riddle
+// edited in the meantime is left for the next run.
+func (s *editorService) PublishDue() error {
+	riddles, err := s.riddleRepo.FindDueScheduled(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, riddle := range riddles {
+		_, err := s.commit(0, riddle.ID, riddle.Version, models.RevisionStatus, nil, func(snapshot *RiddleSnapshot) {
+			snapshot.Status = models.RiddlePublished
+			snapshot.PublishAt = nil
+		})
+		if err != nil && !errors.Is(err, ErrRiddleVersionConflict) {
+			return err
+		}
+	}
+	return nil
+}
+
+// commit applies change to the riddle's current state and saves the result as
+// the next version. The riddle's first recorded edit also stores its previous
+// state, so it can be restored.
+func (s *editorService) commit(editorID, riddleID uint, expectedVersion int, action string, restoredTo *int, change func(*RiddleSnapshot)) (*models.Riddle, error) {
+	riddle, err := s.findRiddle(riddleID)
+	if err != nil {
+		return nil, err
+	}
+	if riddle.Version != expectedVersion {
+		return nil, ErrRiddleVersionConflict
+	}
+
+	before := snapshotOf(riddle)
+	snapshot := before
+	change(&snapshot)
+	if len(diffSnapshots(before, snapshot)) == 0 {
+		return riddle, nil
+	}
+
+	var revisions []models.RiddleRevision
+	recorded, err := s.revisionRepo.Exists(riddle.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		initial, err := newRevision(0, models.RevisionInitial, riddle.Version, before, before)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *initial)
+	}
+
+	revision, err := newRevision(editorID, action, riddle.Version+1, before, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	revision.RestoredTo = restoredTo
+	revisions = append(revisions, *revision)
+	for i := range revisions {
+		revisions[i].RiddleID = riddle.ID
+	}
+
+	snapshot.applyTo(riddle)
+	riddle.Version++
+	updated, err := s.riddleRepo.UpdateWithRevisions(riddle, expectedVersion, revisions...)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrRiddleVersionConflict
+	}
+	return s.riddleRepo.FindByID(riddle.ID)
+}
+
+func (s *editorService) findRiddle(riddleID uint) (*models.Riddle, error) {
+	riddle, err := s.riddleRepo.FindByID(riddleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRiddleNotFound
+		}
+		return nil, err
+	}
+	if riddle.DeletedAt.Valid {
+		return nil, ErrRiddleNotFound
+	}
+	return riddle, nil
+}
+
+func validRiddleStatus(status string) bool {
+	switch status {
+	case models.RiddleDraft, models.RiddleScheduled, models.RiddlePublished, models.RiddleArchived:
+		return true
+	}
+	return false
+}
+
+// newRevision records the snapshot with its changes against before; an
+// editorID of 0 leaves the revision without an author
+func newRevision(editorID uint, action string, version int, before, after RiddleSnapshot) (*models.RiddleRevision, error) {
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := json.Marshal(diffSnapshots(before, after))
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &models.RiddleRevision{
+		Version:  version,
+		Action:   action,
+		Snapshot: string(snapshot),
+		Diff:     string(diff),
+	}
+	if editorID != 0 {
+		revision.AuthorID = &editorID
+	}
+	return revision, nil
+}
+
+func snapshotOf(riddle *models.Riddle) RiddleSnapshot {
+	return RiddleSnapshot{
+		Title:           riddle.Title,
+		Description:     riddle.Description,
+		Answer:          riddle.Answer,
+		AcceptedAnswers: acceptedAnswers(riddle)[1:],
+		Explanation:     riddle.Explanation,
+		CategoryID:      riddle.CategoryID,
+		Difficulty:      riddle.Difficulty,
+		Type:            formatOf(riddle).Type,
+		Payload:         riddle.Payload,
+		Key:             riddle.Key,
+		Status:          riddle.Status,
+		PublishAt:       riddle.PublishAt,
+	}
+}
+
+// setContent replaces everything but the status
+func (snapshot *RiddleSnapshot) setContent(content RiddleSnapshot) {
+	content.Status, content.PublishAt = snapshot.Status, snapshot.PublishAt
+	*snapshot = content
+}
+
+func (snapshot RiddleSnapshot) applyTo(riddle *models.Riddle) {
+	riddle.Title = snapshot.Title
+	riddle.Description = snapshot.Description
+	riddle.Answer = snapshot.Answer
+	riddle.AcceptedAnswers = strings.Join(snapshot.AcceptedAnswers, "\n")
+	riddle.Explanation = snapshot.Explanation
+	riddle.CategoryID = snapshot.CategoryID
+	riddle.Difficulty = snapshot.Difficulty
+	riddle.Type = snapshot.Type
+	riddle.Payload = snapshot.Payload
+	riddle.Key = snapshot.Key
+	riddle.Status = snapshot.Status
+	riddle.PublishAt = snapshot.PublishAt
+}
+
+// diffSnapshots lists the fields that differ, in a fixed order
+func diffSnapshots(before, after RiddleSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field string, old, new interface{}, changed bool) {
+		if changed {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add("title", before.Title, after.Title, before.Title != after.Title)
+	add("description", before.Description, after.Description, before.Description != after.Description)
+	add("answer", before.Answer, after.Answer, before.Answer != after.Answer)
+	add("accepted_answers", before.AcceptedAnswers, after.AcceptedAnswers, !slices.Equal(before.AcceptedAnswers, after.AcceptedAnswers))
+	add("explanation", before.Explanation, after.Explanation, before.Explanation != after.Explanation)
+	add("category_id", before.CategoryID, after.CategoryID, before.CategoryID != after.CategoryID)
+	add("difficulty", before.Difficulty, after.Difficulty, before.Difficulty != after.Difficulty)
+	add("type", before.Type, after.Type, before.Type != after.Type)
+	add("payload", before.Payload, after.Payload, !reflect.DeepEqual(before.Payload, after.Payload))
+	add("key", before.Key, after.Key, !reflect.DeepEqual(before.Key, after.Key))
+	add("status", before.Status, after.Status, before.Status != after.Status)
+	add("publish_at", before.PublishAt, after.PublishAt, !sameTime(before.PublishAt, after.PublishAt))
+	return changes
+}
+
+// cleanAcceptedAnswers trims the answers and drops empty and repeated ones
+func cleanAcceptedAnswers(answers []string) []string {
+	cleaned := []string{}
+	for _, answer := range answers {
+		answer = strings.Join(strings.Fields(answer), " ")
+		if answer != "" && !slices.Contains(cleaned, answer) {
+			cleaned = append(cleaned, answer)
+		}
+	}
+	return cleaned
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
\ No newline at end of file
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	InitialRating = 1500.0
+	// provisionalGames is how many results a rating takes to settle; until then it moves faster
+	provisionalGames = 20
+	provisionalK     = 40.0
+	settledK         = 16.0
+	// nextRiddleChoices is how many of the closest riddles GET /riddles/next picks from
+	nextRiddleChoices = 5
+	// Difficulty buckets by riddle rating, applied once a riddle has enough results
+	easyRatingBelow       = 1400.0
+	hardRatingFrom        = 1600.0
+	recalibrationMinGames = 10
+)
+
+// difficultyRatings are the starting ratings of riddles nobody has played yet
+var difficultyRatings = map[string]float64{
+	"easy":   1300,
+	"medium": 1500,
+	"hard":   1700,
+}
+
+type EloService interface {
+	HandleEvent(event Event) error
+	NextRiddle(userID uint) (*NextRiddle, error)
+	Recalibrate() error
+}
+
+type NextRiddle struct {
+	Riddle       PlayerRiddle `json:"riddle"`
+	PlayerRating float64      `json:"player_rating"`
+	SolveChance  float64      `json:"solve_chance"` // expected by the ratings
+}
+
+type eloService struct {
+	eloRepo      repository.EloRepository
+	userRepo     repository.UserRepository
+	mediaService MediaService
+}
+
+func NewEloService(eloRepo repository.EloRepository, userRepo repository.UserRepository, mediaService MediaService) EloService {
+	return &eloService{
+		eloRepo:      eloRepo,
+		userRepo:     userRepo,
+		mediaService: mediaService,
+	}
+}
+
+// HandleEvent rates the first outcome of every user-riddle pair: a solve on
+// the first attempt is a win (less so with hints), a wrong first answer or a
+// reveal before answering is a loss. Later attempts don't change ratings,
+// and neither do retroactive solves: the original outcome was already rated.
+func (s *eloService) HandleEvent(event Event) error {
+	if event.Retroactive {
+		return nil
+	}
+
+	var score float64
+	switch {
+	case event.Type == EventRiddleSolved && event.Progress.Attempts == 1:
+		score = math.Max(0.5, 1-0.25*float64(event.Progress.HintsUsed))
+	case event.Type == EventAnswerWrong && event.Progress.Attempts == 1:
+		score = 0
+	case event.Type == EventRiddleRevealed && event.Progress.Attempts == 0:
+		score = 0
+	default:
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	expected := expectedScore(user.Rating, event.Riddle.Rating)
+	userDelta := kFactor(user.RatingGames) * (score - expected)
+	riddleDelta := kFactor(event.Riddle.RatingGames) * (expected - score)
+	return s.eloRepo.ApplyResult(user.ID, userDelta, event.Riddle.ID, riddleDelta)
+}
+
+// NextRiddle picks one of the unsolved riddles rated closest to the player;
+// anonymous players are treated as newcomers
+func (s *eloService) NextRiddle(userID uint) (*NextRiddle, error) {
+	rating := InitialRating
+	if userID != 0 {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		rating = user.Rating
+	}
+
+	riddles, err := s.eloRepo.FindUnsolvedNear(userID, rating, nextRiddleChoices)
+	if err != nil {
+		return nil, err
+	}
+	if len(riddles) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	riddle := riddles[rand.Intn(len(riddles))]
+	s.mediaService.Sign(riddle.Media)
+	return &NextRiddle{
+		Riddle:       NewPlayerRiddle(&riddle),
+		PlayerRating: math.Round(rating),
+		SolveChance:  math.Round(expectedScore(rating, riddle.Rating)*100) / 100,
+	}, nil
+}
+
+// Recalibrate gives new riddles a starting rating from their hand-assigned
+// difficulty and shows riddles with enough results in the bucket their
+// rating falls into. The hand-assigned difficulty stays as editors set it.
+func (s *eloService) Recalibrate() error {
+	if err := s.eloRepo.SeedRiddleRatings(difficultyRatings); err != nil {
+		return err
+	}
+	_, err := s.eloRepo.RecalibrateDifficulty(easyRatingBelow, hardRatingFrom, recalibrationMinGames)
+	return err
+}
+
+// expectedScore is the player's expected result against the riddle
+func expectedScore(playerRating, riddleRating float64) float64 {
+	return 1 / (1 + math.Pow(10, (riddleRating-playerRating)/400))
+}
+
+// difficultyForRating maps a rating to the difficulty bucket it falls into
+func difficultyForRating(rating float64) string {
+	switch {
+	case rating < easyRatingBelow:
+		return "easy"
+	case rating < hardRatingFrom:
+		return "medium"
+	}
+	return "hard"
+}
+
+func kFactor(games int) float64 {
+	if games < provisionalGames {
+		return provisionalK
+	}
+	return settledK
+}
\ No newline at end of file
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+	"riddles-server/models"
+)
+
+type EventType string
+
+const (
+	EventRiddleSolved   EventType = "riddle_solved" // first correct answer of a user to a riddle
+	EventAnswerWrong    EventType = "answer_wrong"  // incorrect answer to a riddle the user hasn't solved
+	EventRiddleRated    EventType = "riddle_rated"
+	EventRiddleRevealed EventType = "riddle_revealed" // answer shown to a user who hasn't solved the riddle
+)
+
+// Event describes something a player did; subscribers react to it synchronously
+type Event struct {
+	Type        EventType
+	UserID      uint
+	Riddle      *models.Riddle
+	Progress    *models.UserRiddleProgress // set for answer events
+	Answer      string                     // set for answer events
+	Rating      int                        // set for rating events
+	At          time.Time
+	Retroactive bool // solve credited later, when an editor accepted the answer
+}
+
+type EventHandler func(event Event) error
+
+type EventBus interface {
+	Subscribe(eventType EventType, handler EventHandler)
+	Publish(event Event)
+}
+
+type eventBus struct {
+	mu       sync.RWMutex
+	handlers map[EventType][]EventHandler
+}
+
+func NewEventBus() EventBus {
+	return &eventBus{
+		handlers: make(map[EventType][]EventHandler),
+	}
+}
+
+func (b *eventBus) Subscribe(eventType EventType, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish runs the handlers in subscription order. The action behind the
+// event is already saved by then, so a failing handler is logged rather than
+// failing the request (a retry would repeat the action), and doesn't stop the
+// others.
+func (b *eventBus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(event); err != nil {
+			log.Printf("Handler for %s of user %d failed: %v", event.Type, event.UserID, err)
+		}
+	}
+}
\ No newline at end of file
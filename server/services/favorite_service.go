@@ -26,7 +26,7 @@ func NewFavoriteService(favoriteRepo repository.FavoriteRepository, riddleRepo r
 
 func (s *favoriteService) AddFavorite(userID, riddleID uint) error {
 	// Check if riddle exists
-	_, err := s.riddleRepo.FindByID(riddleID)
+	_, err := s.riddleRepo.FindPublishedByID(riddleID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+	"riddles-server/utils"
+)
+
+// Leaderboard names
+const (
+	BoardAllTime    = "all-time"
+	BoardWeekly     = "weekly"
+	BoardMonthly    = "monthly"
+	BoardDailySpeed = "daily-speed"
+)
+
+// Each solve counts towards leaderboards with a weight based on difficulty
+var solveWeights = map[string]int{
+	"easy":   1,
+	"medium": 2,
+	"hard":   3,
+}
+
+const defaultSolveWeight = 1
+
+type LeaderboardService interface {
+	RecordSolve(progress *models.UserRiddleProgress, riddle *models.Riddle) error
+	GetAllTime(limit int, userID uint) (*Leaderboard, error)
+	GetRolling(board string, limit int, userID uint) (*Leaderboard, error) // weekly or monthly
+	GetCategory(categoryID uint, limit int, userID uint) (*Leaderboard, error)
+	GetDailySpeed(date time.Time, limit int, userID uint) (*Leaderboard, error)
+	Rebuild() error
+}
+
+type Leaderboard struct {
+	Board   string                      `json:"board"`
+	Entries []repository.LeaderboardRow `json:"entries"`
+	Me      *repository.LeaderboardRow  `json:"me"` // the caller's row, even when outside the top
+}
+
+type leaderboardService struct {
+	leaderboardRepo repository.LeaderboardRepository
+	dailyRiddleRepo repository.DailyRiddleRepository
+}
+
+func NewLeaderboardService(leaderboardRepo repository.LeaderboardRepository, dailyRiddleRepo repository.DailyRiddleRepository) LeaderboardService {
+	return &leaderboardService{
+		leaderboardRepo: leaderboardRepo,
+		dailyRiddleRepo: dailyRiddleRepo,
+	}
+}
+
+func (s *leaderboardService) RecordSolve(progress *models.UserRiddleProgress, riddle *models.Riddle) error {
+	weight := solveWeight(riddle.Difficulty)
+	day := utils.GameDate(progress.SolvedAt)
+
+	if err := s.leaderboardRepo.AddScore("all", progress.UserID, weight); err != nil {
+		return err
+	}
+	if err := s.leaderboardRepo.AddScore(categoryBoard(riddle.CategoryID), progress.UserID, weight); err != nil {
+		return err
+	}
+	if err := s.leaderboardRepo.AddDayScore(day, progress.UserID, weight); err != nil {
+		return err
+	}
+
+	featured, err := s.dailyRiddleRepo.IsFeatured(riddle.ID, day)
+	if err != nil {
+		return err
+	}
+	if featured {
+		// Timed from when the riddle was opened; rows from before that was
+		// recorded fall back to when they were created
+		startedAt := progress.CreatedAt
+		if progress.StartedAt != nil {
+			startedAt = *progress.StartedAt
+		}
+		return s.leaderboardRepo.AddDailySpeedSolve(day, progress.UserID, startedAt, progress.SolvedAt)
+	}
+	return nil
+}
+
+func (s *leaderboardService) GetAllTime(limit int, userID uint) (*Leaderboard, error) {
+	rows, err := s.leaderboardRepo.TopByBoard("all", limit, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newLeaderboard(BoardAllTime, rows, limit, userID), nil
+}
+
+func (s *leaderboardService) GetRolling(board string, limit int, userID uint) (*Leaderboard, error) {
+	days := 7
+	if board == BoardMonthly {
+		days = 30
+	}
+
+	since := utils.GameToday().AddDate(0, 0, -days+1)
+	rows, err := s.leaderboardRepo.TopSince(since, limit, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newLeaderboard(board, rows, limit, userID), nil
+}
+
+func (s *leaderboardService) GetCategory(categoryID uint, limit int, userID uint) (*Leaderboard, error) {
+	board := categoryBoard(categoryID)
+	rows, err := s.leaderboardRepo.TopByBoard(board, limit, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newLeaderboard(board, rows, limit, userID), nil
+}
+
+func (s *leaderboardService) GetDailySpeed(date time.Time, limit int, userID uint) (*Leaderboard, error) {
+	rows, err := s.leaderboardRepo.TopDailySpeed(date, limit, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newLeaderboard(BoardDailySpeed, rows, limit, userID), nil
+}
+
+func (s *leaderboardService) Rebuild() error {
+	return s.leaderboardRepo.Rebuild(solveWeights, defaultSolveWeight, utils.GameLocation().String())
+}
+
+// newLeaderboard splits the caller's row from the top N rows
+func newLeaderboard(board string, rows []repository.LeaderboardRow, limit int, userID uint) *Leaderboard {
+	leaderboard := &Leaderboard{
+		Board:   board,
+		Entries: []repository.LeaderboardRow{},
+	}
+
+	for i := range rows {
+		if rows[i].UserID == userID {
+			me := rows[i]
+			leaderboard.Me = &me
+		}
+		if rows[i].Position <= limit {
+			leaderboard.Entries = append(leaderboard.Entries, rows[i])
+		}
+	}
+
+	return leaderboard
+}
+
+func solveWeight(difficulty string) int {
+	if weight, ok := solveWeights[difficulty]; ok {
+		return weight
+	}
+	return defaultSolveWeight
+}
+
+func categoryBoard(categoryID uint) string {
+	return fmt.Sprintf("category:%d", categoryID)
+}
\ No newline at end of file
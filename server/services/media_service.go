@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+	"riddles-server/storage"
+	"riddles-server/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxMediaSize caps one upload
+	MaxMediaSize = 5 << 20
+	// MaxMediaPerRiddle caps the attachments of one riddle
+	MaxMediaPerRiddle = 10
+	// MediaURLLifetime is how long the signed URLs in responses stay valid
+	MediaURLLifetime = time.Hour
+	// maxMediaPixels guards against images that are small files but huge bitmaps
+	maxMediaPixels = 40_000_000
+	thumbnailSide  = 320
+)
+
+// mediaTypes maps the accepted content types to file extensions
+var mediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+var (
+	ErrMediaNotFound     = errors.New("media not found")
+	ErrMediaTooLarge     = errors.New("file is larger than 5 MB")
+	ErrMediaType         = errors.New("file must be a JPEG, PNG or GIF image")
+	ErrTooManyMedia      = errors.New("a riddle can have at most 10 attachments")
+	ErrMediaUnreadable   = errors.New("image could not be read")
+	ErrMediaDimensions   = errors.New("image is too large")
+	ErrMediaTypeMismatch = errors.New("declared content type does not match the file")
+)
+
+// MediaService stores images attached to riddles and signs their URLs
+type MediaService interface {
+	Upload(editorID, riddleID uint, contentType string, file io.Reader) (*models.RiddleMedia, error)
+	GetMedia(riddleID uint) ([]models.RiddleMedia, error)
+	Delete(mediaID uint) error
+	Sign(media []models.RiddleMedia) // fills in the URLs
+}
+
+type mediaService struct {
+	mediaRepo  repository.MediaRepository
+	riddleRepo repository.RiddleRepository
+	store      storage.BlobStore
+}
+
+func NewMediaService(mediaRepo repository.MediaRepository, riddleRepo repository.RiddleRepository, store storage.BlobStore) MediaService {
+	return &mediaService{
+		mediaRepo:  mediaRepo,
+		riddleRepo: riddleRepo,
+		store:      store,
+	}
+}
+
+// Upload checks that the file is an image of an accepted type, whatever the
+// client claims, and stores it with a thumbnail
+func (s *mediaService) Upload(editorID, riddleID uint, contentType string, file io.Reader) (*models.RiddleMedia, error) {
+	riddle, err := s.riddleRepo.FindByID(riddleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRiddleNotFound
+		}
+		return nil, err
+	}
+	if riddle.DeletedAt.Valid {
+		return nil, ErrRiddleNotFound
+	}
+
+	count, err := s.mediaRepo.CountByRiddle(riddleID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxMediaPerRiddle {
+		return nil, ErrTooManyMedia
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxMediaSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxMediaSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	detected := http.DetectContentType(data)
+	ext, ok := mediaTypes[detected]
+	if !ok {
+		return nil, ErrMediaType
+	}
+	if contentType != "" && contentType != "application/octet-stream" && contentType != detected {
+		return nil, ErrMediaTypeMismatch
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrMediaUnreadable
+	}
+	if config.Width*config.Height > maxMediaPixels {
+		return nil, ErrMediaDimensions
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrMediaUnreadable
+	}
+
+	// JPEG has no transparency, so only JPEG sources get JPEG thumbnails
+	var thumbnail bytes.Buffer
+	thumbnailType, thumbnailExt := "image/png", ".png"
+	if detected == "image/jpeg" {
+		thumbnailType, thumbnailExt = "image/jpeg", ".jpg"
+		err = jpeg.Encode(&thumbnail, utils.Thumbnail(img, thumbnailSide), &jpeg.Options{Quality: 80})
+	} else {
+		err = png.Encode(&thumbnail, utils.Thumbnail(img, thumbnailSide))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	media := &models.RiddleMedia{
+		RiddleID:     riddleID,
+		Key:          fmt.Sprintf("riddles/%d/%s%s", riddleID, name, ext),
+		ThumbnailKey: fmt.Sprintf("riddles/%d/%s_thumb%s", riddleID, name, thumbnailExt),
+		ContentType:  detected,
+		Size:         int64(len(data)),
+		Width:        config.Width,
+		Height:       config.Height,
+		Position:     count,
+	}
+	if editorID != 0 {
+		media.UploadedByID = &editorID
+	}
+
+	ctx := context.Background()
+	if err := s.store.Put(ctx, media.Key, detected, bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, media.ThumbnailKey, thumbnailType, &thumbnail, int64(thumbnail.Len())); err != nil {
+		s.removeBlobs(media)
+		return nil, err
+	}
+	if err := s.mediaRepo.Create(media); err != nil {
+		s.removeBlobs(media)
+		return nil, err
+	}
+
+	media.URL = s.store.SignedURL(media.Key, MediaURLLifetime)
+	media.ThumbnailURL = s.store.SignedURL(media.ThumbnailKey, MediaURLLifetime)
+	return media, nil
+}
+
+func (s *mediaService) GetMedia(riddleID uint) ([]models.RiddleMedia, error) {
+	if _, err := s.riddleRepo.FindByID(riddleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRiddleNotFound
+		}
+		return nil, err
+	}
+
+	media, err := s.mediaRepo.FindByRiddle(riddleID)
+	if err != nil {
+		return nil, err
+	}
+	s.Sign(media)
+	return media, nil
+}
+
+func (s *mediaService) Delete(mediaID uint) error {
+	media, err := s.mediaRepo.FindByID(mediaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMediaNotFound
+		}
+		return err
+	}
+
+	if err := s.mediaRepo.Delete(media.ID); err != nil {
+		return err
+	}
+	// A file left behind by a failed delete only costs space
+	s.removeBlobs(media)
+	return nil
+}
+
+func (s *mediaService) Sign(media []models.RiddleMedia) {
+	for i := range media {
+		media[i].URL = s.store.SignedURL(media[i].Key, MediaURLLifetime)
+		media[i].ThumbnailURL = s.store.SignedURL(media[i].ThumbnailKey, MediaURLLifetime)
+	}
+}
+
+func (s *mediaService) removeBlobs(media *models.RiddleMedia) {
+	ctx := context.Background()
+	s.store.Delete(ctx, media.Key)
+	s.store.Delete(ctx, media.ThumbnailKey)
+}
+
+// randomName makes blob names unguessable, so only signed URLs reach them
+func randomName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+)
+
+// notificationsLimit caps how many of the latest notifications are listed
+const notificationsLimit = 50
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationService interface {
+	Notify(userIDs []uint, kind, message, link string) error
+	GetNotifications(userID uint) (*NotificationList, error)
+	MarkRead(userID, notificationID uint) error
+	MarkAllRead(userID uint) error
+}
+
+type NotificationList struct {
+	Unread        int                   `json:"unread"`
+	Notifications []models.Notification `json:"notifications"`
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *notificationService) Notify(userIDs []uint, kind, message, link string) error {
+	notifications := make([]models.Notification, len(userIDs))
+	for i, userID := range userIDs {
+		notifications[i] = models.Notification{
+			UserID:  userID,
+			Kind:    kind,
+			Message: message,
+			Link:    link,
+		}
+	}
+	return s.notificationRepo.CreateBatch(notifications)
+}
+
+func (s *notificationService) GetNotifications(userID uint) (*NotificationList, error) {
+	notifications, err := s.notificationRepo.FindByUser(userID, notificationsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationList{Unread: unread, Notifications: notifications}, nil
+}
+
+func (s *notificationService) MarkRead(userID, notificationID uint) error {
+	found, err := s.notificationRepo.MarkRead(userID, notificationID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(userID uint) error {
+	return s.notificationRepo.MarkAllRead(userID, time.Now())
+}
\ No newline at end of file
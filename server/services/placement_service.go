@@ -0,0 +1,289 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// PlacementSteps is the length of the placement test
+	PlacementSteps = 6
+	// The estimate moves a lot on the first answers and settles towards the end
+	placementMaxK = 160.0
+	placementMinK = 40.0
+)
+
+var (
+	ErrPlacementNotStarted = errors.New("placement test has not been started")
+	ErrPlacementAnswered   = errors.New("placement question already answered")
+)
+
+type PlacementService interface {
+	StartTest(userID uint) (*PlacementView, error)
+	GetTest(userID uint) (*PlacementView, error)
+	SubmitAnswer(userID uint, answer string) (*PlacementView, error)
+}
+
+type PlacementView struct {
+	ID       uint                   `json:"id"`
+	Step     int                    `json:"step"` // answered questions
+	Total    int                    `json:"total"`
+	Finished bool                   `json:"finished"`
+	Current  *PlacementQuestionView `json:"current,omitempty"`
+	Result   *PlacementResult       `json:"result,omitempty"`
+}
+
+type PlacementQuestionView struct {
+	Position    int                  `json:"position"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Category    string               `json:"category"`
+	Type        string               `json:"type"`
+	Payload     models.RiddlePayload `json:"payload"`
+	Media       []models.RiddleMedia `json:"media,omitempty"`
+}
+
+type PlacementResult struct {
+	SkillLevel          string  `json:"skill_level"`
+	PreferredDifficulty string  `json:"preferred_difficulty"`
+	Rating              float64 `json:"rating"`
+	Correct             int     `json:"correct"`
+}
+
+type placementService struct {
+	placementRepo repository.PlacementRepository
+	userRepo      repository.UserRepository
+	mediaService  MediaService
+}
+
+// Placement answers only calibrate the player; they don't count as riddle progress
+func NewPlacementService(placementRepo repository.PlacementRepository, userRepo repository.UserRepository, mediaService MediaService) PlacementService {
+	return &placementService{
+		placementRepo: placementRepo,
+		userRepo:      userRepo,
+		mediaService:  mediaService,
+	}
+}
+
+// StartTest resumes an unfinished test or starts a new one; retaking the test
+// updates the preferences but only seeds the rating of players who haven't
+// been rated by regular play yet
+func (s *placementService) StartTest(userID uint) (*PlacementView, error) {
+	test, err := s.placementRepo.FindLatest(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if test != nil && test.FinishedAt == nil {
+		// A test whose next question couldn't be picked earlier gets another try
+		if currentPlacementStep(test) == nil {
+			if err := s.addStep(test); err != nil {
+				return nil, err
+			}
+		}
+		return s.view(test), nil
+	}
+
+	test = &models.PlacementTest{
+		UserID:          userID,
+		EstimatedRating: InitialRating,
+		StartedAt:       time.Now(),
+	}
+	if err := s.placementRepo.Create(test); err != nil {
+		return nil, err
+	}
+	if err := s.addStep(test); err != nil {
+		return nil, err
+	}
+
+	return s.view(test), nil
+}
+
+func (s *placementService) GetTest(userID uint) (*PlacementView, error) {
+	test, err := s.placementRepo.FindLatest(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlacementNotStarted
+		}
+		return nil, err
+	}
+	return s.view(test), nil
+}
+
+func (s *placementService) SubmitAnswer(userID uint, answer string) (*PlacementView, error) {
+	test, err := s.placementRepo.FindLatest(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlacementNotStarted
+		}
+		return nil, err
+	}
+	if test.FinishedAt != nil {
+		return nil, ErrPlacementNotStarted
+	}
+
+	step := currentPlacementStep(test)
+	if step == nil {
+		return nil, ErrPlacementAnswered
+	}
+
+	now := time.Now()
+	correct := answerMatches(&step.Riddle, answer)
+	answered, err := s.placementRepo.AnswerStep(step.ID, answer, correct, now)
+	if err != nil {
+		return nil, err
+	}
+	if !answered {
+		return nil, ErrPlacementAnswered
+	}
+	step.Answer = answer
+	step.Correct = correct
+	step.AnsweredAt = &now
+
+	// Elo update against the riddle with a K that shrinks as answers come in
+	score := 0.0
+	if correct {
+		score = 1
+	}
+	k := math.Max(placementMinK, placementMaxK/math.Pow(2, float64(step.Position-1)))
+	test.EstimatedRating += k * (score - expectedScore(test.EstimatedRating, step.Riddle.Rating))
+
+	finished := step.Position >= PlacementSteps
+	if finished {
+		test.FinishedAt = &now
+	}
+	// The estimate is saved before the next riddle is picked, so a failed pick
+	// doesn't lose it; StartTest tries the pick again
+	if err := s.placementRepo.Update(test); err != nil {
+		return nil, err
+	}
+
+	if finished {
+		if err := s.savePlacement(test); err != nil {
+			return nil, err
+		}
+	} else if err := s.addStep(test); err != nil {
+		return nil, err
+	}
+	return s.view(test), nil
+}
+
+// addStep picks the next riddle at the difficulty matching the current
+// estimate, preferring categories the test hasn't covered yet
+func (s *placementService) addStep(test *models.PlacementTest) error {
+	var riddleIDs, categoryIDs []uint
+	for _, step := range test.Steps {
+		riddleIDs = append(riddleIDs, step.RiddleID)
+		categoryIDs = append(categoryIDs, step.Riddle.CategoryID)
+	}
+
+	difficulty := difficultyForRating(test.EstimatedRating)
+	riddle, err := s.placementRepo.FindCandidate(test.UserID, difficulty, riddleIDs, categoryIDs)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		riddle, err = s.placementRepo.FindCandidate(test.UserID, difficulty, riddleIDs, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		riddle, err = s.placementRepo.FindCandidate(test.UserID, "", riddleIDs, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotEnoughRiddles
+	}
+	if err != nil {
+		return err
+	}
+
+	step := models.PlacementStep{
+		PlacementTestID: test.ID,
+		Position:        len(test.Steps) + 1,
+		RiddleID:        riddle.ID,
+	}
+	if err := s.placementRepo.AddStep(&step); err != nil {
+		return err
+	}
+
+	step.Riddle = *riddle
+	test.Steps = append(test.Steps, step)
+	return nil
+}
+
+func (s *placementService) savePlacement(test *models.PlacementTest) error {
+	return s.userRepo.SavePlacement(&models.User{
+		ID:                  test.UserID,
+		SkillLevel:          skillLevelForRating(test.EstimatedRating),
+		PreferredDifficulty: difficultyForRating(test.EstimatedRating),
+		Rating:              math.Round(test.EstimatedRating),
+		PlacedAt:            test.FinishedAt,
+	})
+}
+
+func skillLevelForRating(rating float64) string {
+	switch difficultyForRating(rating) {
+	case "easy":
+		return "beginner"
+	case "medium":
+		return "intermediate"
+	}
+	return "advanced"
+}
+
+func currentPlacementStep(test *models.PlacementTest) *models.PlacementStep {
+	for i := range test.Steps {
+		if test.Steps[i].AnsweredAt == nil {
+			return &test.Steps[i]
+		}
+	}
+	return nil
+}
+
+// view signs the media of the current question before building the view
+func (s *placementService) view(test *models.PlacementTest) *PlacementView {
+	if step := currentPlacementStep(test); step != nil {
+		s.mediaService.Sign(step.Riddle.Media)
+	}
+	return newPlacementView(test)
+}
+
+func newPlacementView(test *models.PlacementTest) *PlacementView {
+	view := &PlacementView{
+		ID:       test.ID,
+		Total:    PlacementSteps,
+		Finished: test.FinishedAt != nil,
+	}
+
+	correct := 0
+	for _, step := range test.Steps {
+		if step.AnsweredAt != nil {
+			view.Step++
+		}
+		if step.Correct {
+			correct++
+		}
+	}
+
+	if view.Finished {
+		view.Result = &PlacementResult{
+			SkillLevel:          skillLevelForRating(test.EstimatedRating),
+			PreferredDifficulty: difficultyForRating(test.EstimatedRating),
+			Rating:              math.Round(test.EstimatedRating),
+			Correct:             correct,
+		}
+		return view
+	}
+
+	if step := currentPlacementStep(test); step != nil {
+		view.Current = &PlacementQuestionView{
+			Position:    step.Position,
+			Title:       step.Riddle.Title,
+			Description: step.Riddle.Description,
+			Category:    step.Riddle.Category.Name,
+			Type:        formatOf(&step.Riddle).Type,
+			Payload:     step.Riddle.Payload,
+			Media:       step.Riddle.Media,
+		}
+	}
+	return view
+}
\ No newline at end of file
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"time"
+	"riddles-server/models"
+)
+
+// PlayerRiddle is a riddle as players see it while it can still be answered:
+// the answer, the accepted alternatives and the key stay on the server
+type PlayerRiddle struct {
+	ID          uint                 `json:"id"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	CategoryID  uint                 `json:"category_id"`
+	Category    models.Category      `json:"category"`
+	Difficulty  string               `json:"difficulty"`
+	Type        string               `json:"type"`
+	Payload     models.RiddlePayload `json:"payload"`
+	Media       []models.RiddleMedia `json:"media,omitempty"`
+	Tags        []models.Tag         `json:"tags"`
+	Rating      float64              `json:"rating"`
+	RatingGames int                  `json:"rating_games"`
+	AuthorName  string               `json:"author_name,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+func NewPlayerRiddle(riddle *models.Riddle) PlayerRiddle {
+	return PlayerRiddle{
+		ID:          riddle.ID,
+		Title:       riddle.Title,
+		Description: riddle.Description,
+		CategoryID:  riddle.CategoryID,
+		Category:    riddle.Category,
+		Difficulty:  riddle.DisplayedDifficulty(),
+		Type:        formatOf(riddle).Type,
+		Payload:     riddle.Payload,
+		Media:       riddle.Media,
+		Tags:        riddle.Tags,
+		Rating:      riddle.Rating,
+		RatingGames: riddle.RatingGames,
+		AuthorName:  riddle.AuthorName,
+		CreatedAt:   riddle.CreatedAt,
+		UpdatedAt:   riddle.UpdatedAt,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,528 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"riddles-server/models"
+)
+
+type RoomState string
+
+const (
+	RoomLobby    RoomState = "lobby"
+	RoomQuestion RoomState = "question"
+	RoomReveal   RoomState = "reveal"
+	RoomFinished RoomState = "finished"
+	RoomClosed   RoomState = "closed"
+)
+
+// Messages sent to room clients
+const (
+	RoomMsgWelcome    = "welcome"
+	RoomMsgState      = "state"
+	RoomMsgQuestion   = "question"
+	RoomMsgAnswer     = "answer_result"
+	RoomMsgReveal     = "reveal"
+	RoomMsgScoreboard = "scoreboard"
+	RoomMsgFinished   = "finished"
+	RoomMsgError      = "error"
+)
+
+// Messages accepted from room clients
+const (
+	RoomCmdStart  = "start"  // host only
+	RoomCmdNext   = "next"   // host only, skips the rest of the reveal pause
+	RoomCmdAnswer = "answer" // players only
+)
+
+const (
+	roomCorrectPoints = 100
+	roomSpeedBonus    = 50 // scaled by the share of the countdown left
+	roomSendBuffer    = 32
+)
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	ErrRoomClosed   = errors.New("room is closed")
+	ErrRoomStarted  = errors.New("game already started")
+	ErrRoomKey      = errors.New("unknown player key")
+)
+
+type RoomOptions struct {
+	Questions       int    `json:"questions"`
+	CategoryID      uint   `json:"category_id"`
+	Difficulty      string `json:"difficulty"`
+	QuestionSeconds int    `json:"question_seconds"`
+	RevealSeconds   int    `json:"reveal_seconds"`
+}
+
+// RoomMessage is a server-to-client message; unused fields are omitted
+type RoomMessage struct {
+	Type       string              `json:"type"`
+	State      RoomState           `json:"state,omitempty"`
+	PlayerID   int                 `json:"player_id,omitempty"`
+	Key        string              `json:"key,omitempty"`
+	IsHost     bool                `json:"is_host,omitempty"`
+	Index      int                 `json:"index,omitempty"` // 1-based question number
+	Total      int                 `json:"total,omitempty"`
+	Question   *RoomRiddle         `json:"question,omitempty"`
+	Deadline   *time.Time          `json:"deadline,omitempty"`
+	Answered   bool                `json:"answered,omitempty"`
+	Correct    *bool               `json:"correct,omitempty"`
+	Points     int                 `json:"points,omitempty"`
+	Answer     string              `json:"answer,omitempty"` // correct answer, only in reveal messages
+	Scoreboard []RoomScoreboardRow `json:"scoreboard,omitempty"`
+	Message    string              `json:"message,omitempty"`
+}
+
+type RoomRiddle struct {
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Category    string               `json:"category"`
+	Difficulty  string               `json:"difficulty"`
+	Type        string               `json:"type"`
+	Payload     models.RiddlePayload `json:"payload"`
+	Media       []models.RiddleMedia `json:"media,omitempty"`
+}
+
+type RoomScoreboardRow struct {
+	PlayerID  int    `json:"player_id"`
+	Name      string `json:"name"`
+	Score     int    `json:"score"`
+	Correct   int    `json:"correct"`
+	Connected bool   `json:"connected"`
+}
+
+// RoomClientMessage is a client-to-server message
+type RoomClientMessage struct {
+	Type   string `json:"type"`
+	Answer string `json:"answer"`
+}
+
+type roomPlayer struct {
+	id      int
+	key     string
+	name    string
+	host    bool
+	score   int
+	correct int
+	send    chan RoomMessage // outbox of the current connection, nil while disconnected
+}
+
+// Room runs a multiplayer quiz: the host starts it, then each riddle is shown
+// with a countdown, followed by a short reveal with the live scoreboard.
+// Players are identified by a key so they can reconnect without losing score.
+type Room struct {
+	Code string
+
+	mu       sync.Mutex
+	options  RoomOptions
+	riddles  []models.Riddle
+	state    RoomState
+	current  int // index into riddles
+	started  time.Time
+	deadline time.Time
+	timer    *time.Timer
+	timerGen int
+	players  map[string]*roomPlayer // by key
+	nextID   int
+	answered map[string]bool
+	sign     func(media []models.RiddleMedia)
+	onFinish func(room *Room)
+}
+
+// sign fills in media URLs; rooms outlive the URLs, so every question
+// message gets freshly signed ones
+func newRoom(code string, riddles []models.Riddle, options RoomOptions, sign func(media []models.RiddleMedia), onFinish func(room *Room)) *Room {
+	return &Room{
+		Code:     code,
+		options:  options,
+		riddles:  riddles,
+		state:    RoomLobby,
+		players:  make(map[string]*roomPlayer),
+		answered: make(map[string]bool),
+		sign:     sign,
+		onFinish: onFinish,
+	}
+}
+
+// RoomConn is a single client connection to a room
+type RoomConn struct {
+	room   *Room
+	player *roomPlayer
+	send   chan RoomMessage
+}
+
+// Messages returns the outbox of the connection; it's closed when the
+// connection is replaced by a reconnect, the client is too slow, or the room closes
+func (c *RoomConn) Messages() <-chan RoomMessage {
+	return c.send
+}
+
+func (c *RoomConn) Handle(msg RoomClientMessage) {
+	c.room.handle(c, msg)
+}
+
+func (c *RoomConn) Close() {
+	c.room.disconnect(c)
+}
+
+func (r *Room) State() RoomState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// addPlayer registers a player that can connect later with the returned key
+func (r *Room) addPlayer(name string, host bool) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	r.nextID++
+	r.players[key] = &roomPlayer{id: r.nextID, key: key, name: name, host: host}
+	return key, nil
+}
+
+// Join connects a client. An existing key resumes that player and replaces its
+// previous connection; otherwise a new player is created with the given name.
+func (r *Room) Join(key, name string) (*RoomConn, error) {
+	if key == "" {
+		if r.State() != RoomLobby {
+			return nil, ErrRoomStarted
+		}
+		var err error
+		if key, err = r.addPlayer(name, false); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == RoomClosed {
+		return nil, ErrRoomClosed
+	}
+
+	player, ok := r.players[key]
+	if !ok {
+		return nil, ErrRoomKey
+	}
+
+	if player.send != nil {
+		close(player.send)
+	}
+	conn := &RoomConn{room: r, player: player, send: make(chan RoomMessage, roomSendBuffer)}
+	player.send = conn.send
+
+	r.sendTo(player, RoomMessage{
+		Type:     RoomMsgWelcome,
+		State:    r.state,
+		PlayerID: player.id,
+		Key:      player.key,
+		IsHost:   player.host,
+		Total:    len(r.riddles),
+	})
+	r.sendSnapshot(player)
+	r.broadcastScoreboard()
+
+	return conn, nil
+}
+
+func (r *Room) disconnect(c *RoomConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A reconnect may already have replaced this connection
+	if c.player.send != c.send {
+		return
+	}
+	close(c.player.send)
+	c.player.send = nil
+
+	if r.state != RoomClosed {
+		r.broadcastScoreboard()
+	}
+}
+
+func (r *Room) handle(c *RoomConn, msg RoomClientMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c.player.send != c.send {
+		return
+	}
+
+	switch msg.Type {
+	case RoomCmdStart:
+		if !c.player.host {
+			r.sendTo(c.player, roomError("only the host can start the game"))
+			return
+		}
+		if r.state != RoomLobby {
+			r.sendTo(c.player, roomError(ErrRoomStarted.Error()))
+			return
+		}
+		r.started = time.Now()
+		r.startQuestion(0)
+	case RoomCmdNext:
+		if !c.player.host {
+			r.sendTo(c.player, roomError("only the host can skip ahead"))
+			return
+		}
+		if r.state == RoomReveal {
+			r.advance()
+		}
+	case RoomCmdAnswer:
+		r.answer(c.player, msg.Answer)
+	default:
+		r.sendTo(c.player, roomError("unknown message type"))
+	}
+}
+
+func (r *Room) answer(player *roomPlayer, text string) {
+	if player.host {
+		r.sendTo(player, roomError("the host doesn't answer"))
+		return
+	}
+	if r.state != RoomQuestion || time.Now().After(r.deadline) {
+		r.sendTo(player, roomError("no question is open"))
+		return
+	}
+	if r.answered[player.key] {
+		r.sendTo(player, roomError("already answered"))
+		return
+	}
+	r.answered[player.key] = true
+
+	correct := answerMatches(&r.riddles[r.current], text)
+	points := 0
+	if correct {
+		total := time.Duration(r.options.QuestionSeconds) * time.Second
+		left := time.Until(r.deadline)
+		points = roomCorrectPoints + int(float64(roomSpeedBonus)*float64(left)/float64(total))
+		player.score += points
+		player.correct++
+	}
+
+	r.sendTo(player, RoomMessage{
+		Type:    RoomMsgAnswer,
+		Index:   r.current + 1,
+		Correct: &correct,
+		Points:  points,
+	})
+
+	if r.allAnswered() {
+		r.reveal()
+	}
+}
+
+func (r *Room) startQuestion(index int) {
+	r.state = RoomQuestion
+	r.current = index
+	r.answered = make(map[string]bool)
+	r.deadline = time.Now().Add(time.Duration(r.options.QuestionSeconds) * time.Second)
+
+	for _, player := range r.players {
+		r.sendSnapshot(player)
+	}
+	r.schedule(time.Until(r.deadline), r.reveal)
+}
+
+func (r *Room) reveal() {
+	if r.state != RoomQuestion {
+		return
+	}
+	r.state = RoomReveal
+	r.deadline = time.Now().Add(time.Duration(r.options.RevealSeconds) * time.Second)
+
+	r.broadcast(RoomMessage{
+		Type:       RoomMsgReveal,
+		State:      r.state,
+		Index:      r.current + 1,
+		Total:      len(r.riddles),
+		Answer:     SolutionOf(&r.riddles[r.current]),
+		Deadline:   r.deadlineRef(),
+		Scoreboard: r.scoreboard(),
+	})
+	r.schedule(time.Until(r.deadline), r.advance)
+}
+
+func (r *Room) advance() {
+	if r.state != RoomReveal {
+		return
+	}
+	if r.current+1 < len(r.riddles) {
+		r.startQuestion(r.current + 1)
+		return
+	}
+
+	r.state = RoomFinished
+	r.stopTimer()
+	r.broadcast(RoomMessage{
+		Type:       RoomMsgFinished,
+		State:      r.state,
+		Total:      len(r.riddles),
+		Scoreboard: r.scoreboard(),
+	})
+	if r.onFinish != nil {
+		go r.onFinish(r)
+	}
+}
+
+// close disconnects everyone; the room can't be joined afterwards
+func (r *Room) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.state = RoomClosed
+	r.stopTimer()
+	for _, player := range r.players {
+		if player.send != nil {
+			close(player.send)
+			player.send = nil
+		}
+	}
+}
+
+// schedule runs fn under the room lock after d, replacing any pending timer
+func (r *Room) schedule(d time.Duration, fn func()) {
+	r.stopTimer()
+	generation := r.timerGen
+	r.timer = time.AfterFunc(d, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		// A timer that fired while being replaced must not act on the next phase
+		if r.timerGen == generation {
+			fn()
+		}
+	})
+}
+
+func (r *Room) stopTimer() {
+	r.timerGen++
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+// deadlineRef returns a copy for messages, which are encoded outside the lock
+func (r *Room) deadlineRef() *time.Time {
+	deadline := r.deadline
+	return &deadline
+}
+
+func (r *Room) allAnswered() bool {
+	for key, player := range r.players {
+		if !player.host && player.send != nil && !r.answered[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// sendSnapshot brings a (re)connected player up to date with the current phase
+func (r *Room) sendSnapshot(player *roomPlayer) {
+	switch r.state {
+	case RoomQuestion:
+		riddle := r.riddles[r.current]
+		// A copy, since messages already queued may still hold the previous URLs
+		media := slices.Clone(riddle.Media)
+		r.sign(media)
+		r.sendTo(player, RoomMessage{
+			Type:  RoomMsgQuestion,
+			State: r.state,
+			Index: r.current + 1,
+			Total: len(r.riddles),
+			Question: &RoomRiddle{
+				Title:       riddle.Title,
+				Description: riddle.Description,
+				Category:    riddle.Category.Name,
+				Difficulty:  riddle.DisplayedDifficulty(),
+				Type:        formatOf(&riddle).Type,
+				Payload:     riddle.Payload,
+				Media:       media,
+			},
+			Deadline: r.deadlineRef(),
+			Answered: r.answered[player.key],
+		})
+	case RoomReveal:
+		r.sendTo(player, RoomMessage{
+			Type:       RoomMsgReveal,
+			State:      r.state,
+			Index:      r.current + 1,
+			Total:      len(r.riddles),
+			Answer:     SolutionOf(&r.riddles[r.current]),
+			Deadline:   r.deadlineRef(),
+			Scoreboard: r.scoreboard(),
+		})
+	case RoomFinished:
+		r.sendTo(player, RoomMessage{
+			Type:       RoomMsgFinished,
+			State:      r.state,
+			Total:      len(r.riddles),
+			Scoreboard: r.scoreboard(),
+		})
+	default:
+		r.sendTo(player, RoomMessage{Type: RoomMsgState, State: r.state, Total: len(r.riddles)})
+	}
+}
+
+func (r *Room) broadcastScoreboard() {
+	r.broadcast(RoomMessage{Type: RoomMsgScoreboard, State: r.state, Scoreboard: r.scoreboard()})
+}
+
+func (r *Room) broadcast(msg RoomMessage) {
+	for _, player := range r.players {
+		r.sendTo(player, msg)
+	}
+}
+
+// sendTo never blocks; a client that can't keep up is disconnected and has to
+// reconnect, which resends the current state
+func (r *Room) sendTo(player *roomPlayer, msg RoomMessage) {
+	if player.send == nil {
+		return
+	}
+	select {
+	case player.send <- msg:
+	default:
+		close(player.send)
+		player.send = nil
+	}
+}
+
+func (r *Room) scoreboard() []RoomScoreboardRow {
+	rows := make([]RoomScoreboardRow, 0, len(r.players))
+	for _, player := range r.players {
+		if player.host {
+			continue
+		}
+		rows = append(rows, RoomScoreboardRow{
+			PlayerID:  player.id,
+			Name:      player.name,
+			Score:     player.score,
+			Correct:   player.correct,
+			Connected: player.send != nil,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Score != rows[j].Score {
+			return rows[i].Score > rows[j].Score
+		}
+		return rows[i].PlayerID < rows[j].PlayerID
+	})
+	return rows
+}
+
+func roomError(message string) RoomMessage {
+	return RoomMessage{Type: RoomMsgError, Message: message}
+}
\ No newline at end of file
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultQuizQuestions = 10
+	maxQuizQuestions     = 50
+	defaultQuizTimeLimit = 5 * time.Minute
+	minQuizTimeLimit     = 30 * time.Second
+	maxQuizTimeLimit     = time.Hour
+	// quizGracePeriod absorbs network latency for answers sent right before the deadline
+	quizGracePeriod = 2 * time.Second
+)
+
+var (
+	ErrQuizNotFound        = errors.New("quiz not found")
+	ErrQuizOver            = errors.New("quiz is finished or out of time")
+	ErrQuizQuestionInvalid = errors.New("quiz question not found")
+	ErrQuizAlreadyAnswered = errors.New("question already answered")
+	ErrNotEnoughRiddles    = errors.New("not enough riddles match the filters")
+)
+
+type QuizService interface {
+	CreateQuiz(userID uint, options QuizOptions) (*QuizView, error)
+	GetQuiz(userID, quizID uint) (*QuizView, error)
+	SubmitAnswer(userID, quizID uint, position int, answer string) (*QuizAnswerResult, error)
+	FinishQuiz(userID, quizID uint) (*QuizView, error)
+}
+
+type QuizOptions struct {
+	Questions        int    `json:"questions"`
+	CategoryID       uint   `json:"category_id"`
+	Difficulty       string `json:"difficulty"`
+	TimeLimitSeconds int    `json:"time_limit_seconds"`
+}
+
+// QuizView is what the player sees; correct answers are only included once the quiz is over
+type QuizView struct {
+	ID               uint               `json:"id"`
+	CategoryID       *uint              `json:"category_id"`
+	Difficulty       string             `json:"difficulty,omitempty"`
+	TimeLimitSeconds int                `json:"time_limit_seconds"`
+	StartedAt        time.Time          `json:"started_at"`
+	ExpiresAt        time.Time          `json:"expires_at"`
+	RemainingSeconds int                `json:"remaining_seconds"`
+	Finished         bool               `json:"finished"`
+	FinishedAt       *time.Time         `json:"finished_at,omitempty"`
+	Questions        []QuizQuestionView `json:"questions"`
+	Summary          *QuizSummary       `json:"summary,omitempty"`
+}
+
+type QuizQuestionView struct {
+	Position      int                  `json:"position"`
+	RiddleID      uint                 `json:"riddle_id,omitempty"` // only once the quiz is over, so the riddle can't be looked up meanwhile
+	Title         string               `json:"title"`
+	Description   string               `json:"description"`
+	Category      string               `json:"category"`
+	Difficulty    string               `json:"difficulty"`
+	Type          string               `json:"type"`
+	Payload       models.RiddlePayload `json:"payload"`
+	Media         []models.RiddleMedia `json:"media,omitempty"`
+	Answered      bool                 `json:"answered"`
+	Answer        string               `json:"answer,omitempty"`
+	Correct       *bool                `json:"correct,omitempty"`
+	CorrectAnswer string               `json:"correct_answer,omitempty"`
+}
+
+type QuizSummary struct {
+	Correct         int `json:"correct"`
+	Answered        int `json:"answered"`
+	Total           int `json:"total"`
+	Score           int `json:"score"`
+	DurationSeconds int `json:"duration_seconds"`
+}
+
+type QuizAnswerResult struct {
+	Position         int  `json:"position"`
+	Correct          bool `json:"correct"`
+	RemainingSeconds int  `json:"remaining_seconds"`
+}
+
+type quizService struct {
+	quizRepo     repository.QuizRepository
+	riddleRepo   repository.RiddleRepository
+	mediaService MediaService
+}
+
+// Quizzes are a separate game mode: their answers don't count towards riddle
+// progress, streaks or points.
+func NewQuizService(quizRepo repository.QuizRepository, riddleRepo repository.RiddleRepository, mediaService MediaService) QuizService {
+	return &quizService{
+		quizRepo:     quizRepo,
+		riddleRepo:   riddleRepo,
+		mediaService: mediaService,
+	}
+}
+
+func (s *quizService) CreateQuiz(userID uint, options QuizOptions) (*QuizView, error) {
+	count := options.Questions
+	if count <= 0 {
+		count = defaultQuizQuestions
+	}
+	if count > maxQuizQuestions {
+		count = maxQuizQuestions
+	}
+
+	timeLimit := time.Duration(options.TimeLimitSeconds) * time.Second
+	if timeLimit <= 0 {
+		timeLimit = defaultQuizTimeLimit
+	}
+	if timeLimit < minQuizTimeLimit {
+		timeLimit = minQuizTimeLimit
+	}
+	if timeLimit > maxQuizTimeLimit {
+		timeLimit = maxQuizTimeLimit
+	}
+
+	riddles, err := s.riddleRepo.FindRandom(options.CategoryID, options.Difficulty, count)
+	if err != nil {
+		return nil, err
+	}
+	if len(riddles) == 0 {
+		return nil, ErrNotEnoughRiddles
+	}
+
+	now := time.Now()
+	session := &models.QuizSession{
+		UserID:           userID,
+		Difficulty:       options.Difficulty,
+		TimeLimitSeconds: int(timeLimit.Seconds()),
+		StartedAt:        now,
+		ExpiresAt:        now.Add(timeLimit),
+	}
+	if options.CategoryID != 0 {
+		session.CategoryID = &options.CategoryID
+	}
+	for i, riddle := range riddles {
+		session.Questions = append(session.Questions, models.QuizQuestion{
+			Position: i + 1,
+			RiddleID: riddle.ID,
+		})
+	}
+
+	if err := s.quizRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	for i := range session.Questions {
+		session.Questions[i].Riddle = riddles[i]
+	}
+	return s.view(session, now), nil
+}
+
+func (s *quizService) GetQuiz(userID, quizID uint) (*QuizView, error) {
+	session, err := s.findSession(userID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.FinishedAt == nil && now.After(session.ExpiresAt) {
+		if err := s.finish(session, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.view(session, now), nil
+}
+
+func (s *quizService) SubmitAnswer(userID, quizID uint, position int, answer string) (*QuizAnswerResult, error) {
+	session, err := s.findSession(userID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.FinishedAt != nil || now.After(session.ExpiresAt.Add(quizGracePeriod)) {
+		return nil, ErrQuizOver
+	}
+
+	if position < 1 || position > len(session.Questions) {
+		return nil, ErrQuizQuestionInvalid
+	}
+	question := session.Questions[position-1]
+
+	correct := answerMatches(&question.Riddle, answer)
+	ok, err := s.quizRepo.AnswerQuestion(question.ID, answer, correct, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrQuizAlreadyAnswered
+	}
+
+	return &QuizAnswerResult{
+		Position:         position,
+		Correct:          correct,
+		RemainingSeconds: remainingSeconds(session, now),
+	}, nil
+}
+
+func (s *quizService) FinishQuiz(userID, quizID uint) (*QuizView, error) {
+	session, err := s.findSession(userID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.FinishedAt == nil {
+		if err := s.finish(session, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.view(session, now), nil
+}
+
+func (s *quizService) findSession(userID, quizID uint) (*models.QuizSession, error) {
+	session, err := s.quizRepo.FindByID(quizID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && session.UserID != userID) {
+		return nil, ErrQuizNotFound
+	}
+	return session, err
+}
+
+// finish closes the session; a quiz that ran out of time finishes at its deadline
+func (s *quizService) finish(session *models.QuizSession, now time.Time) error {
+	finishedAt := now
+	if finishedAt.After(session.ExpiresAt) {
+		finishedAt = session.ExpiresAt
+	}
+
+	session.FinishedAt = &finishedAt
+	session.Score = quizScore(session)
+	return s.quizRepo.Finish(session)
+}
+
+// quizScore sums the difficulty points of correctly answered questions
+func quizScore(session *models.QuizSession) int {
+	score := 0
+	for _, question := range session.Questions {
+		if question.Correct {
+			score += basePoints(question.Riddle.Difficulty)
+		}
+	}
+	return score
+}
+
+func remainingSeconds(session *models.QuizSession, now time.Time) int {
+	if session.FinishedAt != nil || now.After(session.ExpiresAt) {
+		return 0
+	}
+	return int(session.ExpiresAt.Sub(now).Seconds())
+}
+
+// view signs the media of the questions before building the view
+func (s *quizService) view(session *models.QuizSession, now time.Time) *QuizView {
+	for i := range session.Questions {
+		s.mediaService.Sign(session.Questions[i].Riddle.Media)
+	}
+	return newQuizView(session, now)
+}
+
+func newQuizView(session *models.QuizSession, now time.Time) *QuizView {
+	finished := session.FinishedAt != nil
+	view := &QuizView{
+		ID:               session.ID,
+		CategoryID:       session.CategoryID,
+		Difficulty:       session.Difficulty,
+		TimeLimitSeconds: session.TimeLimitSeconds,
+		StartedAt:        session.StartedAt,
+		ExpiresAt:        session.ExpiresAt,
+		RemainingSeconds: remainingSeconds(session, now),
+		Finished:         finished,
+		FinishedAt:       session.FinishedAt,
+		Questions:        make([]QuizQuestionView, len(session.Questions)),
+	}
+
+	summary := &QuizSummary{Total: len(session.Questions), Score: session.Score}
+	for i, question := range session.Questions {
+		questionView := QuizQuestionView{
+			Position:    question.Position,
+			Title:       question.Riddle.Title,
+			Description: question.Riddle.Description,
+			Category:    question.Riddle.Category.Name,
+			Difficulty:  question.Riddle.DisplayedDifficulty(),
+			Type:        formatOf(&question.Riddle).Type,
+			Payload:     question.Riddle.Payload,
+			Media:       question.Riddle.Media,
+			Answered:    question.AnsweredAt != nil,
+		}
+		if question.AnsweredAt != nil {
+			correct := question.Correct
+			questionView.Answer = question.Answer
+			questionView.Correct = &correct
+			summary.Answered++
+			if correct {
+				summary.Correct++
+			}
+		}
+		if finished {
+			questionView.RiddleID = question.RiddleID
+			questionView.CorrectAnswer = SolutionOf(&question.Riddle)
+		}
+		view.Questions[i] = questionView
+	}
+
+	if finished {
+		summary.DurationSeconds = int(session.FinishedAt.Sub(session.StartedAt).Seconds())
+		view.Summary = summary
+	}
+
+	return view
+}
\ No newline at end of file
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"time"
 	"riddles-server/models"
 	"riddles-server/repository"
 )
@@ -16,12 +17,14 @@ type RatingService interface {
 type ratingService struct {
 	ratingRepo repository.RatingRepository
 	riddleRepo repository.RiddleRepository
+	events     EventBus
 }
 
-func NewRatingService(ratingRepo repository.RatingRepository, riddleRepo repository.RiddleRepository) RatingService {
+func NewRatingService(ratingRepo repository.RatingRepository, riddleRepo repository.RiddleRepository, events EventBus) RatingService {
 	return &ratingService{
 		ratingRepo: ratingRepo,
 		riddleRepo: riddleRepo,
+		events:     events,
 	}
 }
 
@@ -32,7 +35,7 @@ func (s *ratingService) RateRiddle(userID, riddleID uint, rating int) error {
 	}
 
 	// Check if riddle exists
-	_, err := s.riddleRepo.FindByID(riddleID)
+	riddle, err := s.riddleRepo.FindPublishedByID(riddleID)
 	if err != nil {
 		return err
 	}
@@ -44,7 +47,18 @@ func (s *ratingService) RateRiddle(userID, riddleID uint, rating int) error {
 		Rating:   rating,
 	}
 
-	return s.ratingRepo.CreateOrUpdate(riddleRating)
+	if err := s.ratingRepo.CreateOrUpdate(riddleRating); err != nil {
+		return err
+	}
+
+	s.events.Publish(Event{
+		Type:   EventRiddleRated,
+		UserID: userID,
+		Riddle: riddle,
+		Rating: rating,
+		At:     time.Now(),
+	})
+	return nil
 }
 
 func (s *ratingService) RemoveRating(userID, riddleID uint) error {
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+)
+
+const (
+	// recommendationsPerUser is how many entries the job keeps per user
+	recommendationsPerUser = 30
+	// popularListSize is larger so it can still fill lists for players who solved the top riddles
+	popularListSize        = 100
+	defaultRecommendations = 10
+	maxRecommendations     = 30
+	// Weights of the score parts; similarity dominates, popularity breaks ties
+	categoryAffinityWeight   = 0.3
+	difficultyAffinityWeight = 0.2
+	popularityWeight         = 0.1
+)
+
+// Recommendation reasons
+const (
+	ReasonSimilar  = "similar"  // liked by players with the same taste
+	ReasonAffinity = "affinity" // from categories and difficulties the user enjoys
+	ReasonPopular  = "popular"
+)
+
+type RecommendationService interface {
+	GetRecommendations(userID uint, limit int) ([]RecommendedRiddle, error)
+	Precompute() error
+}
+
+type RecommendedRiddle struct {
+	Riddle PlayerRiddle `json:"riddle"`
+	Score  float64      `json:"score"`
+	Reason string       `json:"reason"`
+}
+
+type recommendationService struct {
+	recommendationRepo repository.RecommendationRepository
+	riddleRepo         repository.RiddleRepository
+	mediaService       MediaService
+}
+
+func NewRecommendationService(recommendationRepo repository.RecommendationRepository, riddleRepo repository.RiddleRepository, mediaService MediaService) RecommendationService {
+	return &recommendationService{
+		recommendationRepo: recommendationRepo,
+		riddleRepo:         riddleRepo,
+		mediaService:       mediaService,
+	}
+}
+
+// GetRecommendations serves the precomputed list, topped up from the
+// popularity list for anonymous players and users with little history
+func (s *recommendationService) GetRecommendations(userID uint, limit int) ([]RecommendedRiddle, error) {
+	if limit <= 0 {
+		limit = defaultRecommendations
+	}
+	if limit > maxRecommendations {
+		limit = maxRecommendations
+	}
+
+	var entries []models.Recommendation
+	if userID != 0 {
+		personal, err := s.recommendationRepo.FindForUser(userID, userID, nil, limit)
+		if err != nil {
+			return nil, err
+		}
+		entries = personal
+	}
+
+	if len(entries) < limit {
+		exclude := make([]uint, 0, len(entries))
+		for _, entry := range entries {
+			exclude = append(exclude, entry.RiddleID)
+		}
+		popular, err := s.recommendationRepo.FindForUser(0, userID, exclude, limit-len(entries))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, popular...)
+	}
+
+	result := make([]RecommendedRiddle, len(entries))
+	for i, entry := range entries {
+		s.mediaService.Sign(entry.Riddle.Media)
+		result[i] = RecommendedRiddle{
+			Riddle: NewPlayerRiddle(&entry.Riddle),
+			Score:  math.Round(entry.Score*1000) / 1000,
+			Reason: entry.Reason,
+		}
+	}
+	return result, nil
+}
+
+// Precompute rebuilds every list. Riddles are compared by cosine similarity
+// of the users interested in them; a candidate scores by its similarity to
+// the riddles the user liked (and against the ones they disliked), plus the
+// user's affinity to its category and difficulty, plus overall popularity.
+func (s *recommendationService) Precompute() error {
+	signals, err := s.recommendationRepo.LoadSignals()
+	if err != nil {
+		return err
+	}
+	riddles, err := s.riddleRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	riddleByID := make(map[uint]*models.Riddle, len(riddles))
+	for i := range riddles {
+		riddleByID[riddles[i].ID] = &riddles[i]
+	}
+
+	byUser := make(map[uint]map[uint]float64)
+	popularity := make(map[uint]float64)
+	norms := make(map[uint]float64)
+	for _, signal := range signals {
+		if riddleByID[signal.RiddleID] == nil {
+			continue
+		}
+		if byUser[signal.UserID] == nil {
+			byUser[signal.UserID] = make(map[uint]float64)
+		}
+		byUser[signal.UserID][signal.RiddleID] = signal.Weight
+		if signal.Weight > 0 {
+			popularity[signal.RiddleID] += signal.Weight
+			norms[signal.RiddleID] += signal.Weight * signal.Weight
+		}
+	}
+
+	// Co-occurrence of positive interest, turned into cosine similarity
+	similarity := make(map[uint]map[uint]float64)
+	for _, items := range byUser {
+		for i, wi := range items {
+			if wi <= 0 {
+				continue
+			}
+			for j, wj := range items {
+				if i == j || wj <= 0 {
+					continue
+				}
+				if similarity[i] == nil {
+					similarity[i] = make(map[uint]float64)
+				}
+				similarity[i][j] += wi * wj
+			}
+		}
+	}
+	for i, row := range similarity {
+		for j := range row {
+			row[j] /= math.Sqrt(norms[i] * norms[j])
+		}
+	}
+
+	maxPopularity := 0.0
+	for _, value := range popularity {
+		maxPopularity = math.Max(maxPopularity, value)
+	}
+	normalizedPopularity := func(riddleID uint) float64 {
+		if maxPopularity == 0 {
+			return 0
+		}
+		return popularity[riddleID] / maxPopularity
+	}
+
+	now := time.Now()
+	for userID, items := range byUser {
+		recommendations := recommendForUser(userID, items, riddles, riddleByID, similarity, normalizedPopularity, now)
+		if err := s.recommendationRepo.Replace(userID, recommendations); err != nil {
+			return err
+		}
+	}
+
+	popular := make([]models.Recommendation, 0, len(riddles))
+	for _, riddle := range riddles {
+		popular = append(popular, models.Recommendation{
+			RiddleID:   riddle.ID,
+			Score:      normalizedPopularity(riddle.ID),
+			Reason:     ReasonPopular,
+			ComputedAt: now,
+		})
+	}
+	return s.recommendationRepo.Replace(0, topRecommendations(popular, popularListSize))
+}
+
+func recommendForUser(
+	userID uint,
+	items map[uint]float64,
+	riddles []models.Riddle,
+	riddleByID map[uint]*models.Riddle,
+	similarity map[uint]map[uint]float64,
+	popularity func(uint) float64,
+	now time.Time,
+) []models.Recommendation {
+	// Share of the user's positive interest per category and difficulty
+	categories := make(map[uint]float64)
+	difficulties := make(map[string]float64)
+	total := 0.0
+	for riddleID, weight := range items {
+		if weight <= 0 {
+			continue
+		}
+		categories[riddleByID[riddleID].CategoryID] += weight
+		difficulties[riddleByID[riddleID].Difficulty] += weight
+		total += weight
+	}
+
+	recommendations := make([]models.Recommendation, 0, len(riddles))
+	for _, riddle := range riddles {
+		// Anything the user already interacted with is known to them
+		if _, seen := items[riddle.ID]; seen {
+			continue
+		}
+
+		similar := 0.0
+		for riddleID, weight := range items {
+			similar += similarity[riddleID][riddle.ID] * weight
+		}
+		affinity := 0.0
+		if total > 0 {
+			affinity = categoryAffinityWeight*categories[riddle.CategoryID]/total +
+				difficultyAffinityWeight*difficulties[riddle.Difficulty]/total
+		}
+
+		reason := ReasonPopular
+		switch {
+		case similar > affinity && similar > 0:
+			reason = ReasonSimilar
+		case affinity > 0:
+			reason = ReasonAffinity
+		}
+
+		recommendations = append(recommendations, models.Recommendation{
+			UserID:     userID,
+			RiddleID:   riddle.ID,
+			Score:      similar + affinity + popularityWeight*popularity(riddle.ID),
+			Reason:     reason,
+			ComputedAt: now,
+		})
+	}
+	return topRecommendations(recommendations, recommendationsPerUser)
+}
+
+func topRecommendations(recommendations []models.Recommendation, limit int) []models.Recommendation {
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].RiddleID < recommendations[j].RiddleID
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations
+}
\ No newline at end of file
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+// ReportReasons lists the reasons a riddle can be reported for
+var ReportReasons = []string{models.ReportWrongAnswer, models.ReportDuplicate, models.ReportOffensive, models.ReportTypo}
+
+const reportCommentMaxLength = 1000
+
+var (
+	ErrReportReasonInvalid   = errors.New("reason must be wrong_answer, duplicate, offensive or typo")
+	ErrReportCommentTooLong  = errors.New("comment must be at most 1000 characters")
+	ErrReportDuplicate       = errors.New("you have already reported this riddle for that reason")
+	ErrReportOutcomeInvalid  = errors.New("outcome must be fixed or dismissed")
+	ErrReportFixLinkRequired = errors.New("a link to the fix is required")
+	ErrReportNoteRequired    = errors.New("a note explaining the dismissal is required")
+	ErrNoOpenReports         = errors.New("riddle has no open reports")
+)
+
+type ReportService interface {
+	Report(userID, riddleID uint, reason, comment string) (*models.RiddleReport, error)
+	GetUserReports(userID uint) ([]models.RiddleReport, error)
+	GetQueue() ([]ReportGroup, error)
+	Resolve(moderatorID, riddleID uint, input ResolutionInput) (*models.ReportResolution, error)
+}
+
+type ResolutionInput struct {
+	Outcome string `json:"outcome"` // fixed or dismissed
+	Note    string `json:"note"`
+	FixLink string `json:"fix_link"`
+}
+
+// ReportGroup collects the open reports on one riddle for the moderation queue
+type ReportGroup struct {
+	RiddleID        uint           `json:"riddle_id"`
+	RiddleTitle     string         `json:"riddle_title"`
+	Answer          string         `json:"answer"`
+	Total           int            `json:"total"`
+	Reasons         map[string]int `json:"reasons"`
+	FirstReportedAt time.Time      `json:"first_reported_at"`
+	Reports         []ReportItem   `json:"reports"`
+}
+
+type ReportItem struct {
+	ID           uint      `json:"id"`
+	ReporterName string    `json:"reporter_name"`
+	Reason       string    `json:"reason"`
+	Comment      string    `json:"comment"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Messages sent to reporters, by outcome
+var reportOutcomeMessages = map[string]string{
+	models.ReportFixed:     "Спасибо! Ошибку в загадке «%s» исправили.",
+	models.ReportDismissed: "Жалоба на загадку «%s» рассмотрена: изменений не потребовалось.",
+}
+
+type reportService struct {
+	reportRepo          repository.ReportRepository
+	riddleRepo          repository.RiddleRepository
+	notificationService NotificationService
+}
+
+func NewReportService(reportRepo repository.ReportRepository, riddleRepo repository.RiddleRepository, notificationService NotificationService) ReportService {
+	return &reportService{
+		reportRepo:          reportRepo,
+		riddleRepo:          riddleRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *reportService) Report(userID, riddleID uint, reason, comment string) (*models.RiddleReport, error) {
+	reason = strings.ToLower(strings.TrimSpace(reason))
+	if !slices.Contains(ReportReasons, reason) {
+		return nil, ErrReportReasonInvalid
+	}
+	comment = strings.TrimSpace(comment)
+	if len([]rune(comment)) > reportCommentMaxLength {
+		return nil, ErrReportCommentTooLong
+	}
+
+	if _, err := s.riddleRepo.FindPublishedByID(riddleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRiddleNotFound
+		}
+		return nil, err
+	}
+
+	open, err := s.reportRepo.HasOpen(riddleID, userID, reason)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrReportDuplicate
+	}
+
+	report := &models.RiddleReport{
+		RiddleID:   riddleID,
+		ReporterID: userID,
+		Reason:     reason,
+		Comment:    comment,
+		Status:     models.ReportOpen,
+	}
+	if err := s.reportRepo.Create(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *reportService) GetUserReports(userID uint) ([]models.RiddleReport, error) {
+	return s.reportRepo.FindByReporter(userID)
+}
+
+// GetQueue groups open reports by riddle, most reported first
+func (s *reportService) GetQueue() ([]ReportGroup, error) {
+	reports, err := s.reportRepo.FindOpen()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []ReportGroup{}
+	index := make(map[uint]int)
+	for _, report := range reports {
+		i, ok := index[report.RiddleID]
+		if !ok {
+			i = len(groups)
+			index[report.RiddleID] = i
+			groups = append(groups, ReportGroup{
+				RiddleID:        report.RiddleID,
+				RiddleTitle:     report.Riddle.Title,
+				Answer:          SolutionOf(&report.Riddle),
+				Reasons:         make(map[string]int),
+				FirstReportedAt: report.CreatedAt,
+			})
+		}
+
+		group := &groups[i]
+		group.Total++
+		group.Reasons[report.Reason]++
+		group.Reports = append(group.Reports, ReportItem{
+			ID:           report.ID,
+			ReporterName: report.Reporter.Username,
+			Reason:       report.Reason,
+			Comment:      report.Comment,
+			CreatedAt:    report.CreatedAt,
+		})
+	}
+
+	// Reports come oldest first, so ties keep the longest-waiting riddle on top
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Total > groups[j].Total
+	})
+	return groups, nil
+}
+
+// Resolve closes every open report on the riddle and notifies the reporters
+func (s *reportService) Resolve(moderatorID, riddleID uint, input ResolutionInput) (*models.ReportResolution, error) {
+	input.Outcome = strings.ToLower(strings.TrimSpace(input.Outcome))
+	input.Note = strings.TrimSpace(input.Note)
+	input.FixLink = strings.TrimSpace(input.FixLink)
+	switch input.Outcome {
+	case models.ReportFixed:
+		if input.FixLink == "" {
+			return nil, ErrReportFixLinkRequired
+		}
+	case models.ReportDismissed:
+		if input.Note == "" {
+			return nil, ErrReportNoteRequired
+		}
+	default:
+		return nil, ErrReportOutcomeInvalid
+	}
+
+	riddle, err := s.riddleRepo.FindByID(riddleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRiddleNotFound
+		}
+		return nil, err
+	}
+
+	resolution := &models.ReportResolution{
+		RiddleID:    riddleID,
+		ModeratorID: moderatorID,
+		Outcome:     input.Outcome,
+		Note:        input.Note,
+		FixLink:     input.FixLink,
+	}
+	reports, err := s.reportRepo.Resolve(resolution)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, ErrNoOpenReports
+	}
+
+	// One notification per reporter, however many reasons they reported
+	var reporterIDs []uint
+	for _, report := range reports {
+		if !slices.Contains(reporterIDs, report.ReporterID) {
+			reporterIDs = append(reporterIDs, report.ReporterID)
+		}
+	}
+	message := fmt.Sprintf(reportOutcomeMessages[input.Outcome], riddle.Title)
+	if input.Note != "" {
+		message += " " + input.Note
+	}
+	// The reports are already resolved, so a failed notification must not
+	// turn into an error the moderator can't retry
+	if err := s.notificationService.Notify(reporterIDs, models.NotificationReportResolved, message, input.FixLink); err != nil {
+		log.Printf("Failed to notify reporters of riddle %d: %v", riddleID, err)
+		resolution.FailedNotifications = len(reporterIDs)
+	}
+
+	return resolution, nil
+}
\ No newline at end of file
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+	"riddles-server/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReviewLimit = 20
+	maxReviewLimit     = 100
+	initialEaseFactor  = 2.5
+	minEaseFactor      = 1.3
+	// defaultReviewGrade is the SM-2 quality of a correct answer when the player doesn't grade it
+	defaultReviewGrade = 4
+	// failedReviewGrade is the SM-2 quality of a wrong answer
+	failedReviewGrade = 1
+)
+
+var (
+	ErrReviewNotFound     = errors.New("riddle is not in the review queue")
+	ErrReviewNotDue       = errors.New("riddle is not due for review yet")
+	ErrReviewGradeInvalid = errors.New("grade must be between 3 and 5")
+)
+
+type ReviewService interface {
+	HandleEvent(event Event) error
+	Schedule(userID, riddleID uint, reset bool) error
+	GetDue(userID uint, limit int) (*ReviewQueue, error)
+	SubmitReview(userID, riddleID uint, answer string, grade int) (*ReviewResult, error)
+	Backfill(userID uint) (int, error)
+}
+
+type ReviewQueue struct {
+	Date  string           `json:"date"`
+	Total int              `json:"total"` // all due items, the list may be limited
+	Items []ReviewItemView `json:"items"`
+}
+
+type ReviewItemView struct {
+	RiddleID     uint         `json:"riddle_id"`
+	Riddle       PlayerRiddle `json:"riddle"`
+	DueDate      string       `json:"due_date"`
+	IntervalDays int          `json:"interval_days"`
+	Repetitions  int          `json:"repetitions"`
+}
+
+type ReviewResult struct {
+	RiddleID      uint    `json:"riddle_id"`
+	Correct       bool    `json:"correct"`
+	CorrectAnswer string  `json:"correct_answer"`
+	Quality       int     `json:"quality"`
+	EaseFactor    float64 `json:"ease_factor"`
+	IntervalDays  int     `json:"interval_days"`
+	Repetitions   int     `json:"repetitions"`
+	NextDueDate   string  `json:"next_due_date"`
+}
+
+type reviewService struct {
+	reviewRepo   repository.ReviewRepository
+	progressRepo repository.ProgressRepository
+	mediaService MediaService
+}
+
+// Reviews are practice: they reschedule the riddle but don't change progress or points
+func NewReviewService(reviewRepo repository.ReviewRepository, progressRepo repository.ProgressRepository, mediaService MediaService) ReviewService {
+	return &reviewService{
+		reviewRepo:   reviewRepo,
+		progressRepo: progressRepo,
+		mediaService: mediaService,
+	}
+}
+
+// HandleEvent queues riddles the user answered wrong or revealed
+func (s *reviewService) HandleEvent(event Event) error {
+	switch event.Type {
+	case EventAnswerWrong:
+		return s.Schedule(event.UserID, event.Riddle.ID, false)
+	case EventRiddleRevealed:
+		return s.Schedule(event.UserID, event.Riddle.ID, true)
+	}
+	return nil
+}
+
+// Schedule adds the riddle to the queue for tomorrow. An item already in the
+// queue keeps its schedule unless reset, which starts the repetitions over.
+func (s *reviewService) Schedule(userID, riddleID uint, reset bool) error {
+	item, err := s.reviewRepo.FindByUserAndRiddle(userID, riddleID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if item != nil && !reset {
+		return nil
+	}
+	if item == nil {
+		item = &models.ReviewItem{UserID: userID, RiddleID: riddleID, EaseFactor: initialEaseFactor}
+	}
+
+	item.Repetitions = 0
+	item.IntervalDays = 1
+	item.DueDate = utils.GameToday().AddDate(0, 0, 1)
+	return s.reviewRepo.Save(item)
+}
+
+func (s *reviewService) GetDue(userID uint, limit int) (*ReviewQueue, error) {
+	if limit <= 0 {
+		limit = defaultReviewLimit
+	}
+	if limit > maxReviewLimit {
+		limit = maxReviewLimit
+	}
+
+	today := utils.GameToday()
+	items, err := s.reviewRepo.FindDue(userID, today, limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.reviewRepo.CountDue(userID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &ReviewQueue{
+		Date:  today.Format("2006-01-02"),
+		Total: total,
+		Items: make([]ReviewItemView, 0, len(items)),
+	}
+	for _, item := range items {
+		s.mediaService.Sign(item.Riddle.Media)
+		queue.Items = append(queue.Items, ReviewItemView{
+			RiddleID:     item.RiddleID,
+			Riddle:       NewPlayerRiddle(&item.Riddle),
+			DueDate:      item.DueDate.Format("2006-01-02"),
+			IntervalDays: item.IntervalDays,
+			Repetitions:  item.Repetitions,
+		})
+	}
+	return queue, nil
+}
+
+// SubmitReview checks the answer and reschedules the riddle. A correct answer
+// is graded by the player from 3 (hard) to 5 (easy); a wrong one always fails.
+func (s *reviewService) SubmitReview(userID, riddleID uint, answer string, grade int) (*ReviewResult, error) {
+	if grade == 0 {
+		grade = defaultReviewGrade
+	}
+	if grade < 3 || grade > 5 {
+		return nil, ErrReviewGradeInvalid
+	}
+
+	// Riddles that were unpublished since they were queued can't be reviewed
+	item, err := s.reviewRepo.FindPublishedByUserAndRiddle(userID, riddleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	today := utils.GameToday()
+	if utils.DaysBetween(today, item.DueDate) > 0 {
+		return nil, ErrReviewNotDue
+	}
+
+	correct := answerMatches(&item.Riddle, answer)
+	quality := grade
+	if !correct {
+		quality = failedReviewGrade
+	}
+
+	now := time.Now()
+	applySM2(item, quality, today)
+	item.LastReviewedAt = &now
+	if err := s.reviewRepo.Save(item); err != nil {
+		return nil, err
+	}
+
+	return &ReviewResult{
+		RiddleID:      riddleID,
+		Correct:       correct,
+		CorrectAnswer: SolutionOf(&item.Riddle),
+		Quality:       quality,
+		EaseFactor:    item.EaseFactor,
+		IntervalDays:  item.IntervalDays,
+		Repetitions:   item.Repetitions,
+		NextDueDate:   item.DueDate.Format("2006-01-02"),
+	}, nil
+}
+
+// Backfill queues riddles from existing attempt history: revealed ones and
+// ones that took more than one attempt or are still unsolved
+func (s *reviewService) Backfill(userID uint) (int, error) {
+	progress, err := s.progressRepo.FindByUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, p := range progress {
+		failed := p.Attempts > 1 || (!p.Solved && p.Attempts > 0)
+		if !p.Revealed && !failed {
+			continue
+		}
+		if err := s.Schedule(userID, p.RiddleID, false); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// applySM2 updates the item after a review of the given quality (0-5)
+func applySM2(item *models.ReviewItem, quality int, today time.Time) {
+	if quality >= 3 {
+		switch item.Repetitions {
+		case 0:
+			item.IntervalDays = 1
+		case 1:
+			item.IntervalDays = 6
+		default:
+			item.IntervalDays = int(math.Round(float64(item.IntervalDays) * item.EaseFactor))
+		}
+		item.Repetitions++
+	} else {
+		item.Repetitions = 0
+		item.IntervalDays = 1
+	}
+
+	missing := float64(5 - quality)
+	item.EaseFactor = math.Max(minEaseFactor, item.EaseFactor+0.1-missing*(0.08+missing*0.02))
+	item.EaseFactor = math.Round(item.EaseFactor*100) / 100
+	item.DueDate = today.AddDate(0, 0, item.IntervalDays)
+}
\ No newline at end of file
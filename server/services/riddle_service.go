@@ -1,30 +1,59 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"time"
+	"unicode"
 	"riddles-server/models"
 	"riddles-server/repository"
+
+	"gorm.io/gorm"
 )
 
 type RiddleService interface {
 	GetAllRiddles() ([]models.Riddle, error)
+	GetRiddlesForUser(userID uint, filter repository.RiddleFilter) ([]models.Riddle, error) // ordered by the user's preferred difficulty once placed
 	GetRiddleByID(id uint) (*models.Riddle, error)
+	GetRandomRiddle(filter repository.RandomRiddleFilter) (*models.Riddle, error)
 	GetRiddlesByCategory(categoryID uint) ([]models.Riddle, error)
 	GetRiddlesByDifficulty(difficulty string) ([]models.Riddle, error)
 	GetRiddlesByCategoryAndDifficulty(categoryID uint, difficulty string) ([]models.Riddle, error)
 	SearchRiddles(query string) ([]models.Riddle, error)
 	CheckAnswer(riddleID uint, userAnswer string) (bool, error)
+	SubmitAnswer(userID, riddleID uint, userAnswer string) (bool, error) // checks the answer and records user progress
+	GetHint(userID, riddleID uint) (*Hint, error)
+	RevealAnswer(userID, riddleID uint) (*Reveal, error)
+	StartRiddle(userID, riddleID uint) error // records when the player first opened the riddle
 	GetRiddleWithUserProgress(riddleID, userID uint) (*RiddleWithProgress, error)
 	GetRiddlesWithUserProgress(riddles []models.Riddle, userID uint) ([]RiddleWithProgress, error)
 }
 
 type RiddleWithProgress struct {
-	Riddle       models.Riddle `json:"riddle"`
-	IsSolved     bool          `json:"is_solved"`
-	IsFavorite   bool          `json:"is_favorite"`
-	UserRating   int           `json:"user_rating"` // -1, 0, or 1
-	Likes        int           `json:"likes"`
-	Dislikes     int           `json:"dislikes"`
+	Riddle     PlayerRiddle `json:"riddle"`
+	IsSolved   bool         `json:"is_solved"`
+	IsFavorite bool         `json:"is_favorite"`
+	UserRating int          `json:"user_rating"` // -1, 0, or 1
+	Likes      int          `json:"likes"`
+	Dislikes   int          `json:"dislikes"`
+}
+
+// MaxHints is the number of hints available per riddle
+const MaxHints = 2
+
+// ErrHintsUnavailable is returned for riddle types whose answer can't be hinted at
+var ErrHintsUnavailable = errors.New("hints are only available for free-text riddles")
+
+type Hint struct {
+	Text      string `json:"hint"`
+	HintsUsed int    `json:"hints_used"`
+	HintsLeft int    `json:"hints_left"`
+}
+
+type Reveal struct {
+	Answer      string `json:"answer"`
+	Explanation string `json:"explanation,omitempty"`
 }
 
 type riddleService struct {
@@ -32,6 +61,9 @@ type riddleService struct {
 	progressRepo repository.ProgressRepository
 	favoriteRepo repository.FavoriteRepository
 	ratingRepo   repository.RatingRepository
+	userRepo     repository.UserRepository
+	mediaService MediaService
+	events       EventBus
 }
 
 func NewRiddleService(
@@ -39,12 +71,18 @@ func NewRiddleService(
 	progressRepo repository.ProgressRepository,
 	favoriteRepo repository.FavoriteRepository,
 	ratingRepo repository.RatingRepository,
+	userRepo repository.UserRepository,
+	mediaService MediaService,
+	events EventBus,
 ) RiddleService {
 	return &riddleService{
 		riddleRepo:   riddleRepo,
 		progressRepo: progressRepo,
 		favoriteRepo: favoriteRepo,
 		ratingRepo:   ratingRepo,
+		userRepo:     userRepo,
+		mediaService: mediaService,
+		events:       events,
 	}
 }
 
@@ -52,8 +90,32 @@ func (s *riddleService) GetAllRiddles() ([]models.Riddle, error) {
 	return s.riddleRepo.FindAll()
 }
 
+func (s *riddleService) GetRiddlesForUser(userID uint, filter repository.RiddleFilter) ([]models.Riddle, error) {
+	if userID != 0 {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		filter.NearDifficulty = user.PreferredDifficulty
+	}
+
+	filter.Query = strings.TrimSpace(filter.Query)
+	keys := filter.Tags[:0]
+	for _, tag := range filter.Tags {
+		if key := tagKey(tag); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	filter.Tags = keys
+	return s.riddleRepo.FindFiltered(filter)
+}
+
 func (s *riddleService) GetRiddleByID(id uint) (*models.Riddle, error) {
-	return s.riddleRepo.FindByID(id)
+	return s.riddleRepo.FindPublishedByID(id)
+}
+
+func (s *riddleService) GetRandomRiddle(filter repository.RandomRiddleFilter) (*models.Riddle, error) {
+	return s.riddleRepo.FindRandomOne(filter)
 }
 
 func (s *riddleService) GetRiddlesByCategory(categoryID uint) ([]models.Riddle, error) {
@@ -73,24 +135,206 @@ func (s *riddleService) SearchRiddles(query string) ([]models.Riddle, error) {
 }
 
 func (s *riddleService) CheckAnswer(riddleID uint, userAnswer string) (bool, error) {
-	riddle, err := s.riddleRepo.FindByID(riddleID)
+	riddle, err := s.riddleRepo.FindPublishedByID(riddleID)
 	if err != nil {
 		return false, err
 	}
 
-	// Case-insensitive comparison
-	return strings.EqualFold(strings.TrimSpace(userAnswer), strings.TrimSpace(riddle.Answer)), nil
+	return answerMatches(riddle, userAnswer), nil
+}
+
+func (s *riddleService) SubmitAnswer(userID, riddleID uint, userAnswer string) (bool, error) {
+	riddle, err := s.riddleRepo.FindPublishedByID(riddleID)
+	if err != nil {
+		return false, err
+	}
+	correct := answerMatches(riddle, userAnswer)
+
+	progress, err := s.findOrNewProgress(userID, riddleID)
+	if err != nil {
+		return correct, err
+	}
+
+	// Repeated attempts on an already solved riddle don't change progress, and
+	// a revealed riddle can no longer be solved for credit
+	if progress.Solved || progress.Revealed {
+		return correct, nil
+	}
+
+	now := time.Now()
+	progress.Attempts++
+	if correct {
+		progress.Solved = true
+		progress.SolvedAt = now
+	}
+	if err := s.saveProgress(progress); err != nil {
+		return correct, err
+	}
+
+	event := Event{
+		Type:     EventAnswerWrong,
+		UserID:   userID,
+		Riddle:   riddle,
+		Progress: progress,
+		Answer:   userAnswer,
+		At:       now,
+	}
+	if correct {
+		event.Type = EventRiddleSolved
+	}
+
+	s.events.Publish(event)
+	return correct, nil
+}
+
+// acceptedAnswers returns the riddle's answer followed by its alternatives
+func acceptedAnswers(riddle *models.Riddle) []string {
+	answers := []string{riddle.Answer}
+	for _, line := range strings.Split(riddle.AcceptedAnswers, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			answers = append(answers, line)
+		}
+	}
+	return answers
+}
+
+// normalizeAnswer lowercases the answer, treats ё as е and keeps only
+// letters and digits separated by single spaces
+func normalizeAnswer(answer string) string {
+	answer = strings.ReplaceAll(strings.ToLower(answer), "ё", "е")
+	words := strings.FieldsFunc(answer, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+func (s *riddleService) GetHint(userID, riddleID uint) (*Hint, error) {
+	riddle, err := s.riddleRepo.FindPublishedByID(riddleID)
+	if err != nil {
+		return nil, err
+	}
+	if !isTextRiddle(riddle) {
+		return nil, ErrHintsUnavailable
+	}
+
+	progress, err := s.findOrNewProgress(userID, riddleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hints after solving are free, and asking past the last one repeats it
+	if !progress.Solved && progress.HintsUsed < MaxHints {
+		progress.HintsUsed++
+		if err := s.saveProgress(progress); err != nil {
+			return nil, err
+		}
+	}
+
+	level := progress.HintsUsed
+	if level == 0 {
+		level = 1
+	}
+
+	return &Hint{
+		Text:      buildHint(riddle.Answer, level),
+		HintsUsed: progress.HintsUsed,
+		HintsLeft: MaxHints - progress.HintsUsed,
+	}, nil
+}
+
+// RevealAnswer shows the answer; an unsolved riddle is marked as revealed and goes to review
+func (s *riddleService) RevealAnswer(userID, riddleID uint) (*Reveal, error) {
+	riddle, err := s.riddleRepo.FindPublishedByID(riddleID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.findOrNewProgress(userID, riddleID)
+	if err != nil {
+		return nil, err
+	}
+
+	reveal := &Reveal{Answer: SolutionOf(riddle), Explanation: riddle.Explanation}
+	if progress.Solved || progress.Revealed {
+		return reveal, nil
+	}
+
+	now := time.Now()
+	progress.Revealed = true
+	progress.RevealedAt = &now
+	if err := s.saveProgress(progress); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(Event{
+		Type:     EventRiddleRevealed,
+		UserID:   userID,
+		Riddle:   riddle,
+		Progress: progress,
+		At:       now,
+	})
+	return reveal, nil
+}
+
+// StartRiddle stamps the progress with the time the riddle was first shown,
+// which solve times are measured from
+func (s *riddleService) StartRiddle(userID, riddleID uint) error {
+	progress, err := s.findOrNewProgress(userID, riddleID)
+	if err != nil {
+		return err
+	}
+	if progress.StartedAt != nil || progress.Solved || progress.Revealed {
+		return nil
+	}
+
+	now := time.Now()
+	progress.StartedAt = &now
+	return s.saveProgress(progress)
+}
+
+func (s *riddleService) findOrNewProgress(userID, riddleID uint) (*models.UserRiddleProgress, error) {
+	progress, err := s.progressRepo.FindByUserAndRiddle(userID, riddleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.UserRiddleProgress{UserID: userID, RiddleID: riddleID}, nil
+	}
+	return progress, err
+}
+
+func (s *riddleService) saveProgress(progress *models.UserRiddleProgress) error {
+	if progress.ID == 0 {
+		return s.progressRepo.Create(progress)
+	}
+	return s.progressRepo.Update(progress)
+}
+
+// buildHint derives a hint from the answer itself: first its length, then its first letter
+func buildHint(answer string, level int) string {
+	answer = strings.TrimSpace(answer)
+	runes := []rune(answer)
+	if len(runes) == 0 {
+		return ""
+	}
+
+	if level <= 1 {
+		if words := strings.Fields(answer); len(words) > 1 {
+			return fmt.Sprintf("Ответ состоит из %d слов", len(words))
+		}
+		return fmt.Sprintf("Ответ состоит из %d символов", len(runes))
+	}
+
+	return fmt.Sprintf("Ответ начинается на «%s»", strings.ToUpper(string(runes[0])))
 }
 
 func (s *riddleService) GetRiddleWithUserProgress(riddleID, userID uint) (*RiddleWithProgress, error) {
-	riddle, err := s.riddleRepo.FindByID(riddleID)
+	riddle, err := s.riddleRepo.FindPublishedByID(riddleID)
 	if err != nil {
 		return nil, err
 	}
 
 	result := &RiddleWithProgress{
-		Riddle: *riddle,
+		Riddle: NewPlayerRiddle(riddle),
 	}
+	s.mediaService.Sign(result.Riddle.Media)
 
 	// Get user progress
 	progress, err := s.progressRepo.FindByUserAndRiddle(userID, riddleID)
@@ -129,8 +373,9 @@ func (s *riddleService) GetRiddlesWithUserProgress(riddles []models.Riddle, user
 	// This would be more efficient with a batch query, but for simplicity we'll do individual lookups
 	for i, riddle := range riddles {
 		result[i] = RiddleWithProgress{
-			Riddle: riddle,
+			Riddle: NewPlayerRiddle(&riddle),
 		}
+		s.mediaService.Sign(result[i].Riddle.Media)
 
 		// Get user progress
 		progress, err := s.progressRepo.FindByUserAndRiddle(userID, riddle.ID)
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"riddles-server/models"
+)
+
+const (
+	// MaxRiddleOptions caps the options of choice and ordering riddles
+	MaxRiddleOptions      = 10
+	riddleOptionMaxLength = 200
+	riddleOptionIDMaxLen  = 20
+	riddleUnitMaxLength   = 20
+)
+
+// RiddleTypes lists the valid riddle types
+var RiddleTypes = []string{
+	models.RiddleText,
+	models.RiddleSingleChoice,
+	models.RiddleMultipleChoice,
+	models.RiddleOrdering,
+	models.RiddleNumeric,
+	models.RiddleTrueFalse,
+}
+
+// RiddleFormat is the type-specific part of a riddle. Text riddles leave
+// Payload and Key empty; the other types don't use the answer fields.
+type RiddleFormat struct {
+	Type    string               `json:"type"` // text when empty
+	Payload models.RiddlePayload `json:"payload"`
+	Key     models.RiddleKey     `json:"key"`
+}
+
+var textFormat = RiddleFormat{Type: models.RiddleText}
+
+// answerChecker checks players' answers to one riddle. Answers to typed
+// riddles are plain strings too: option IDs separated by commas for choice
+// and ordering riddles, a number, or true/false.
+type answerChecker interface {
+	Check(answer string) bool
+	Solution() string // the correct answer in words, shown once the riddle is over
+}
+
+func checkerFor(riddle *models.Riddle) answerChecker {
+	switch riddle.Type {
+	case models.RiddleSingleChoice, models.RiddleMultipleChoice:
+		return choiceChecker{options: riddle.Payload.Options, correct: riddle.Key.Correct, many: riddle.Type == models.RiddleMultipleChoice}
+	case models.RiddleOrdering:
+		return orderingChecker{options: riddle.Payload.Options, order: riddle.Key.Correct}
+	case models.RiddleNumeric:
+		return numericChecker{min: deref(riddle.Key.Min), max: deref(riddle.Key.Max), unit: riddle.Payload.Unit}
+	case models.RiddleTrueFalse:
+		return trueFalseChecker{truth: riddle.Key.Truth != nil && *riddle.Key.Truth}
+	}
+	return textChecker{riddle: riddle}
+}
+
+// answerMatches checks the answer the way the riddle's type requires
+func answerMatches(riddle *models.Riddle, userAnswer string) bool {
+	return checkerFor(riddle).Check(userAnswer)
+}
+
+// SolutionOf is the riddle's answer as shown to players after revealing it
+func SolutionOf(riddle *models.Riddle) string {
+	return checkerFor(riddle).Solution()
+}
+
+func isTextRiddle(riddle *models.Riddle) bool {
+	return riddle.Type == "" || riddle.Type == models.RiddleText
+}
+
+type textChecker struct {
+	riddle *models.Riddle
+}
+
+// Check compares the answer with the riddle's answer and its accepted
+// alternatives, ignoring case, ё/е, punctuation and extra spaces
+func (c textChecker) Check(answer string) bool {
+	if strings.EqualFold(strings.TrimSpace(answer), strings.TrimSpace(c.riddle.Answer)) {
+		return true
+	}
+
+	normalized := normalizeAnswer(answer)
+	if normalized == "" {
+		return false
+	}
+	for _, accepted := range acceptedAnswers(c.riddle) {
+		if normalizeAnswer(accepted) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+func (c textChecker) Solution() string {
+	return c.riddle.Answer
+}
+
+type choiceChecker struct {
+	options []models.RiddleOption
+	correct []string
+	many    bool
+}
+
+// Check wants exactly the correct options, in any order
+func (c choiceChecker) Check(answer string) bool {
+	picked := answerIDs(answer)
+	slices.Sort(picked)
+	picked = slices.Compact(picked)
+	if !c.many && len(picked) != 1 {
+		return false
+	}
+
+	correct := slices.Clone(c.correct)
+	slices.Sort(correct)
+	return slices.Equal(picked, correct)
+}
+
+func (c choiceChecker) Solution() string {
+	var texts []string
+	for _, option := range c.options {
+		if slices.Contains(c.correct, option.ID) {
+			texts = append(texts, option.Text)
+		}
+	}
+	return strings.Join(texts, ", ")
+}
+
+type orderingChecker struct {
+	options []models.RiddleOption
+	order   []string
+}
+
+func (c orderingChecker) Check(answer string) bool {
+	return slices.Equal(answerIDs(answer), c.order)
+}
+
+func (c orderingChecker) Solution() string {
+	texts := make([]string, 0, len(c.order))
+	for _, id := range c.order {
+		for _, option := range c.options {
+			if option.ID == id {
+				texts = append(texts, option.Text)
+			}
+		}
+	}
+	return strings.Join(texts, " → ")
+}
+
+type numericChecker struct {
+	min, max float64
+	unit     string
+}
+
+// Check reads the first number of the answer, so "12,5 км" and "12,5км"
+// count as 12.5
+func (c numericChecker) Check(answer string) bool {
+	fields := strings.Fields(strings.ReplaceAll(answer, ",", "."))
+	if len(fields) == 0 {
+		return false
+	}
+	number := strings.TrimRightFunc(fields[0], func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return false
+	}
+	return value >= c.min && value <= c.max
+}
+
+func (c numericChecker) Solution() string {
+	solution := formatNumber(c.min)
+	if c.max != c.min {
+		solution = fmt.Sprintf("от %s до %s", formatNumber(c.min), formatNumber(c.max))
+	}
+	if c.unit != "" {
+		solution += " " + c.unit
+	}
+	return solution
+}
+
+type trueFalseChecker struct {
+	truth bool
+}
+
+var (
+	trueAnswers  = []string{"true", "да", "верно", "правда"}
+	falseAnswers = []string{"false", "нет", "неверно", "ложь"}
+)
+
+func (c trueFalseChecker) Check(answer string) bool {
+	answer = normalizeAnswer(answer)
+	if c.truth {
+		return slices.Contains(trueAnswers, answer)
+	}
+	return slices.Contains(falseAnswers, answer)
+}
+
+func (c trueFalseChecker) Solution() string {
+	if c.truth {
+		return "Верно"
+	}
+	return "Неверно"
+}
+
+// answerIDs splits an answer into option IDs
+func answerIDs(answer string) []string {
+	return strings.FieldsFunc(strings.ToLower(answer), func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func deref(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// normalized trims the format, defaults the type to text and gives options
+// without an ID a letter
+func (format RiddleFormat) normalized() RiddleFormat {
+	format.Type = strings.ToLower(strings.TrimSpace(format.Type))
+	if format.Type == "" {
+		format.Type = models.RiddleText
+	}
+
+	var options []models.RiddleOption
+	for i, option := range format.Payload.Options {
+		option.ID = strings.ToLower(strings.TrimSpace(option.ID))
+		option.Text = strings.TrimSpace(option.Text)
+		if option.ID == "" && i < 26 {
+			option.ID = string(rune('a' + i))
+		}
+		options = append(options, option)
+	}
+	format.Payload.Options = options
+	format.Payload.Unit = strings.TrimSpace(format.Payload.Unit)
+
+	var correct []string
+	for _, id := range format.Key.Correct {
+		correct = append(correct, strings.ToLower(strings.TrimSpace(id)))
+	}
+	format.Key.Correct = correct
+	return format
+}
+
+// check validates the payload and key against the type's schema
+func (format RiddleFormat) check() error {
+	payload, key := format.Payload, format.Key
+	switch format.Type {
+	case models.RiddleText:
+		if len(payload.Options) > 0 || payload.Unit != "" || len(key.Correct) > 0 || key.Min != nil || key.Max != nil || key.Truth != nil {
+			return fmt.Errorf("%w: text riddles have no payload or key", ErrRiddleInvalid)
+		}
+	case models.RiddleSingleChoice, models.RiddleMultipleChoice:
+		if err := checkOptions(payload.Options); err != nil {
+			return err
+		}
+		switch {
+		case len(key.Correct) == 0:
+			return fmt.Errorf("%w: choice riddles need a correct option", ErrRiddleInvalid)
+		case format.Type == models.RiddleSingleChoice && len(key.Correct) > 1:
+			return fmt.Errorf("%w: single choice riddles have exactly one correct option", ErrRiddleInvalid)
+		}
+		for i, id := range key.Correct {
+			if !hasOption(payload.Options, id) {
+				return fmt.Errorf("%w: correct option %q is not among the options", ErrRiddleInvalid, id)
+			}
+			if slices.Contains(key.Correct[:i], id) {
+				return fmt.Errorf("%w: correct option %q is listed twice", ErrRiddleInvalid, id)
+			}
+		}
+	case models.RiddleOrdering:
+		if err := checkOptions(payload.Options); err != nil {
+			return err
+		}
+		if len(key.Correct) != len(payload.Options) {
+			return fmt.Errorf("%w: the correct order must list every option once", ErrRiddleInvalid)
+		}
+		for i, id := range key.Correct {
+			if !hasOption(payload.Options, id) || slices.Contains(key.Correct[:i], id) {
+				return fmt.Errorf("%w: the correct order must list every option once", ErrRiddleInvalid)
+			}
+		}
+	case models.RiddleNumeric:
+		switch {
+		case key.Min == nil || key.Max == nil:
+			return fmt.Errorf("%w: numeric riddles need min and max", ErrRiddleInvalid)
+		case *key.Min > *key.Max:
+			return fmt.Errorf("%w: min must not exceed max", ErrRiddleInvalid)
+		case len([]rune(payload.Unit)) > riddleUnitMaxLength:
+			return fmt.Errorf("%w: unit is too long", ErrRiddleInvalid)
+		}
+	case models.RiddleTrueFalse:
+		if key.Truth == nil {
+			return fmt.Errorf("%w: true/false riddles need truth", ErrRiddleInvalid)
+		}
+	default:
+		return fmt.Errorf("%w: type must be one of %s", ErrRiddleInvalid, strings.Join(RiddleTypes, ", "))
+	}
+
+	if format.Type != models.RiddleNumeric && payload.Unit != "" {
+		return fmt.Errorf("%w: only numeric riddles have a unit", ErrRiddleInvalid)
+	}
+	if format.Type != models.RiddleNumeric && (key.Min != nil || key.Max != nil) {
+		return fmt.Errorf("%w: only numeric riddles have min and max", ErrRiddleInvalid)
+	}
+	if format.Type != models.RiddleTrueFalse && key.Truth != nil {
+		return fmt.Errorf("%w: only true/false riddles have truth", ErrRiddleInvalid)
+	}
+	return nil
+}
+
+func checkOptions(options []models.RiddleOption) error {
+	if len(options) < 2 || len(options) > MaxRiddleOptions {
+		return fmt.Errorf("%w: a riddle needs 2 to %d options", ErrRiddleInvalid, MaxRiddleOptions)
+	}
+	for i, option := range options {
+		switch {
+		case option.ID == "" || option.Text == "":
+			return fmt.Errorf("%w: every option needs an ID and a text", ErrRiddleInvalid)
+		case len([]rune(option.ID)) > riddleOptionIDMaxLen || strings.ContainsAny(option.ID, ",; "):
+			return fmt.Errorf("%w: option ID %q must be short and without commas, semicolons or spaces", ErrRiddleInvalid, option.ID)
+		case len([]rune(option.Text)) > riddleOptionMaxLength:
+			return fmt.Errorf("%w: option %q is too long", ErrRiddleInvalid, option.ID)
+		case hasOption(options[:i], option.ID):
+			return fmt.Errorf("%w: option ID %q is used twice", ErrRiddleInvalid, option.ID)
+		}
+	}
+	return nil
+}
+
+func hasOption(options []models.RiddleOption, id string) bool {
+	return slices.ContainsFunc(options, func(option models.RiddleOption) bool {
+		return option.ID == id
+	})
+}
+
+// formatOf returns the type-specific part of a stored riddle
+func formatOf(riddle *models.Riddle) RiddleFormat {
+	format := RiddleFormat{Type: riddle.Type, Payload: riddle.Payload, Key: riddle.Key}
+	if format.Type == "" {
+		format.Type = models.RiddleText
+	}
+	return format
+}
+
+func (format RiddleFormat) applyTo(riddle *models.Riddle) {
+	riddle.Type = format.Type
+	riddle.Payload = format.Payload
+	riddle.Key = format.Key
+}
\ No newline at end of file
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"riddles-server/models"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func truth(value bool) *bool {
+	return &value
+}
+
+var testOptions = []models.RiddleOption{
+	{ID: "a", Text: "Меркурий"},
+	{ID: "b", Text: "Венера"},
+	{ID: "c", Text: "Земля"},
+}
+
+func TestAnswerMatches(t *testing.T) {
+	text := &models.Riddle{Answer: "Ёжик", AcceptedAnswers: "еж\nколючий ёж"}
+	single := &models.Riddle{Type: models.RiddleSingleChoice, Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"b"}}}
+	multiple := &models.Riddle{Type: models.RiddleMultipleChoice, Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"a", "c"}}}
+	ordering := &models.Riddle{Type: models.RiddleOrdering, Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"c", "a", "b"}}}
+	numeric := &models.Riddle{Type: models.RiddleNumeric, Payload: models.RiddlePayload{Unit: "км"}, Key: models.RiddleKey{Min: float(12), Max: float(13)}}
+	exact := &models.Riddle{Type: models.RiddleNumeric, Key: models.RiddleKey{Min: float(-3), Max: float(-3)}}
+	trueRiddle := &models.Riddle{Type: models.RiddleTrueFalse, Key: models.RiddleKey{Truth: truth(true)}}
+	falseRiddle := &models.Riddle{Type: models.RiddleTrueFalse, Key: models.RiddleKey{Truth: truth(false)}}
+
+	tests := []struct {
+		name   string
+		riddle *models.Riddle
+		answer string
+		want   bool
+	}{
+		{"text exact", text, "Ёжик", true},
+		{"text case and ё", text, "  ЕЖИК ", true},
+		{"text accepted answer", text, "Колючий, ёж!", true},
+		{"text wrong", text, "заяц", false},
+		{"text punctuation only", text, "?!", false},
+
+		{"single choice", single, "b", true},
+		{"single choice upper case", single, " B ", true},
+		{"single choice repeated", single, "b,b", true},
+		{"single choice wrong", single, "a", false},
+		{"single choice two picks", single, "a,b", false},
+		{"single choice empty", single, "", false},
+
+		{"multiple choice", multiple, "a,c", true},
+		{"multiple choice any order", multiple, "c; a", true},
+		{"multiple choice duplicates", multiple, "a, c, a", true},
+		{"multiple choice missing one", multiple, "a", false},
+		{"multiple choice extra", multiple, "a,b,c", false},
+
+		{"ordering", ordering, "c,a,b", true},
+		{"ordering with spaces", ordering, "C A B", true},
+		{"ordering wrong", ordering, "a,b,c", false},
+		{"ordering missing", ordering, "c,a", false},
+		{"ordering repeated", ordering, "c,a,b,b", false},
+
+		{"numeric in range", numeric, "12.5", true},
+		{"numeric comma and unit", numeric, "12,5 км", true},
+		{"numeric unit without space", numeric, "12,5км", true},
+		{"numeric bounds inclusive", numeric, "13", true},
+		{"numeric out of range", numeric, "13,01", false},
+		{"numeric not a number", numeric, "двенадцать", false},
+		{"numeric empty", numeric, " ", false},
+		{"numeric negative", exact, "-3°", true},
+
+		{"true", trueRiddle, "Да", true},
+		{"true in words", trueRiddle, "Верно!", true},
+		{"true answered false", trueRiddle, "нет", false},
+		{"false", falseRiddle, "false", true},
+		{"false answered true", falseRiddle, "правда", false},
+	}
+	for _, test := range tests {
+		if got := answerMatches(test.riddle, test.answer); got != test.want {
+			t.Errorf("%s: answerMatches(%q) = %v, want %v", test.name, test.answer, got, test.want)
+		}
+	}
+}
+
+func TestSolutionOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		riddle *models.Riddle
+		want   string
+	}{
+		{"text", &models.Riddle{Answer: "Эхо"}, "Эхо"},
+		{"choice in option order", &models.Riddle{Type: models.RiddleMultipleChoice, Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"c", "a"}}}, "Меркурий, Земля"},
+		{"ordering", &models.Riddle{Type: models.RiddleOrdering, Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"c", "a", "b"}}}, "Земля → Меркурий → Венера"},
+		{"numeric range", &models.Riddle{Type: models.RiddleNumeric, Payload: models.RiddlePayload{Unit: "км"}, Key: models.RiddleKey{Min: float(12), Max: float(12.5)}}, "от 12 до 12.5 км"},
+		{"numeric exact", &models.Riddle{Type: models.RiddleNumeric, Key: models.RiddleKey{Min: float(7), Max: float(7)}}, "7"},
+		{"false", &models.Riddle{Type: models.RiddleTrueFalse, Key: models.RiddleKey{Truth: truth(false)}}, "Неверно"},
+	}
+	for _, test := range tests {
+		if got := SolutionOf(test.riddle); got != test.want {
+			t.Errorf("%s: SolutionOf = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRiddleFormatCheck(t *testing.T) {
+	unnamed := []models.RiddleOption{{Text: "Меркурий"}, {Text: "Венера"}}
+
+	tests := []struct {
+		name   string
+		format RiddleFormat
+		valid  bool
+	}{
+		{"empty type is text", RiddleFormat{}, true},
+		{"text with a unit", RiddleFormat{Type: "text", Payload: models.RiddlePayload{Unit: "км"}}, false},
+		{"text with a key", RiddleFormat{Type: "text", Key: models.RiddleKey{Truth: truth(true)}}, false},
+		{"unknown type", RiddleFormat{Type: "essay"}, false},
+
+		{"single choice", RiddleFormat{Type: "single_choice", Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"B"}}}, true},
+		{"options get letters", RiddleFormat{Type: "single_choice", Payload: models.RiddlePayload{Options: unnamed}, Key: models.RiddleKey{Correct: []string{"b"}}}, true},
+		{"single choice with two correct", RiddleFormat{Type: "single_choice", Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"a", "b"}}}, false},
+		{"choice without correct", RiddleFormat{Type: "multiple_choice", Payload: models.RiddlePayload{Options: testOptions}}, false},
+		{"correct not an option", RiddleFormat{Type: "multiple_choice", Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"a", "d"}}}, false},
+		{"correct listed twice", RiddleFormat{Type: "multiple_choice", Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"a", "a"}}}, false},
+		{"one option", RiddleFormat{Type: "single_choice", Payload: models.RiddlePayload{Options: testOptions[:1]}, Key: models.RiddleKey{Correct: []string{"a"}}}, false},
+		{"option ID with a comma", RiddleFormat{Type: "single_choice", Payload: models.RiddlePayload{Options: []models.RiddleOption{{ID: "a,b", Text: "x"}, {ID: "c", Text: "y"}}}, Key: models.RiddleKey{Correct: []string{"c"}}}, false},
+		{"option ID used twice", RiddleFormat{Type: "single_choice", Payload: models.RiddlePayload{Options: []models.RiddleOption{{ID: "a", Text: "x"}, {ID: "A", Text: "y"}}}, Key: models.RiddleKey{Correct: []string{"a"}}}, false},
+		{"choice with a unit", RiddleFormat{Type: "single_choice", Payload: models.RiddlePayload{Options: testOptions, Unit: "км"}, Key: models.RiddleKey{Correct: []string{"a"}}}, false},
+
+		{"ordering", RiddleFormat{Type: "ordering", Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"c", "a", "b"}}}, true},
+		{"ordering missing an option", RiddleFormat{Type: "ordering", Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"c", "a"}}}, false},
+		{"ordering repeating an option", RiddleFormat{Type: "ordering", Payload: models.RiddlePayload{Options: testOptions}, Key: models.RiddleKey{Correct: []string{"c", "a", "a"}}}, false},
+
+		{"numeric", RiddleFormat{Type: "numeric", Payload: models.RiddlePayload{Unit: "км"}, Key: models.RiddleKey{Min: float(12), Max: float(13)}}, true},
+		{"numeric without max", RiddleFormat{Type: "numeric", Key: models.RiddleKey{Min: float(12)}}, false},
+		{"numeric min above max", RiddleFormat{Type: "numeric", Key: models.RiddleKey{Min: float(13), Max: float(12)}}, false},
+		{"numeric with truth", RiddleFormat{Type: "numeric", Key: models.RiddleKey{Min: float(1), Max: float(1), Truth: truth(true)}}, false},
+
+		{"true/false", RiddleFormat{Type: "true_false", Key: models.RiddleKey{Truth: truth(false)}}, true},
+		{"true/false without truth", RiddleFormat{Type: "true_false"}, false},
+		{"true/false with a unit", RiddleFormat{Type: "true_false", Payload: models.RiddlePayload{Unit: "км"}, Key: models.RiddleKey{Truth: truth(true)}}, false},
+		{"true/false with a range", RiddleFormat{Type: "true_false", Key: models.RiddleKey{Truth: truth(true), Min: float(1), Max: float(2)}}, false},
+	}
+	for _, test := range tests {
+		err := test.format.normalized().check()
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, ErrRiddleInvalid) {
+			t.Errorf("%s: error = %v, want ErrRiddleInvalid", test.name, err)
+		}
+	}
+}
\ No newline at end of file
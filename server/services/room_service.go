@@ -0,0 +1,159 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+	"riddles-server/repository"
+)
+
+const (
+	defaultRoomQuestions       = 10
+	maxRoomQuestions           = 30
+	defaultRoomQuestionSeconds = 30
+	defaultRoomRevealSeconds   = 5
+	// The caps keep even the longest game well within roomMaxLifetime
+	maxRoomQuestionSeconds = 120
+	maxRoomRevealSeconds   = 30
+	// Finished rooms stay around for a while so players can see the final scoreboard
+	roomFinishedTTL = 10 * time.Minute
+	// Rooms that never finish are dropped after this long
+	roomMaxLifetime = 3 * time.Hour
+)
+
+const (
+	roomCodeLength   = 6
+	roomCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no 0/O or 1/I lookalikes
+)
+
+type RoomService interface {
+	CreateRoom(options RoomOptions) (*RoomInfo, error)
+	GetRoom(code string) (*Room, error)
+}
+
+type RoomInfo struct {
+	Code      string      `json:"code"`
+	HostKey   string      `json:"host_key"` // the host connects with this key
+	Questions int         `json:"questions"`
+	Options   RoomOptions `json:"options"`
+}
+
+type roomService struct {
+	riddleRepo   repository.RiddleRepository
+	mediaService MediaService
+
+	mu    sync.Mutex
+	rooms map[string]*Room
+}
+
+// Rooms live in memory only, so they don't survive a restart and need a
+// single server instance
+func NewRoomService(riddleRepo repository.RiddleRepository, mediaService MediaService) RoomService {
+	return &roomService{
+		riddleRepo:   riddleRepo,
+		mediaService: mediaService,
+		rooms:        make(map[string]*Room),
+	}
+}
+
+func (s *roomService) CreateRoom(options RoomOptions) (*RoomInfo, error) {
+	if options.Questions <= 0 {
+		options.Questions = defaultRoomQuestions
+	}
+	if options.Questions > maxRoomQuestions {
+		options.Questions = maxRoomQuestions
+	}
+	if options.QuestionSeconds <= 0 {
+		options.QuestionSeconds = defaultRoomQuestionSeconds
+	}
+	if options.QuestionSeconds > maxRoomQuestionSeconds {
+		options.QuestionSeconds = maxRoomQuestionSeconds
+	}
+	if options.RevealSeconds <= 0 {
+		options.RevealSeconds = defaultRoomRevealSeconds
+	}
+	if options.RevealSeconds > maxRoomRevealSeconds {
+		options.RevealSeconds = maxRoomRevealSeconds
+	}
+
+	riddles, err := s.riddleRepo.FindRandom(options.CategoryID, options.Difficulty, options.Questions)
+	if err != nil {
+		return nil, err
+	}
+	if len(riddles) == 0 {
+		return nil, ErrNotEnoughRiddles
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, err := s.newCode()
+	if err != nil {
+		return nil, err
+	}
+
+	room := newRoom(code, riddles, options, s.mediaService.Sign, func(room *Room) {
+		time.AfterFunc(roomFinishedTTL, func() { s.removeRoom(room) })
+	})
+	hostKey, err := room.addPlayer("Ведущий", true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rooms[code] = room
+	time.AfterFunc(roomMaxLifetime, func() { s.removeRoom(room) })
+
+	return &RoomInfo{
+		Code:      code,
+		HostKey:   hostKey,
+		Questions: len(riddles),
+		Options:   options,
+	}, nil
+}
+
+func (s *roomService) GetRoom(code string) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[strings.ToUpper(code)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+	return room, nil
+}
+
+func (s *roomService) removeRoom(room *Room) {
+	s.mu.Lock()
+	if s.rooms[room.Code] == room {
+		delete(s.rooms, room.Code)
+	}
+	s.mu.Unlock()
+
+	room.close()
+}
+
+// newCode returns an unused join code; the caller holds s.mu
+func (s *roomService) newCode() (string, error) {
+	for {
+		buf := make([]byte, roomCodeLength)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for i := range buf {
+			buf[i] = roomCodeAlphabet[int(buf[i])%len(roomCodeAlphabet)]
+		}
+		if _, taken := s.rooms[string(buf)]; !taken {
+			return string(buf), nil
+		}
+	}
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
\ No newline at end of file
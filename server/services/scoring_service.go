@@ -0,0 +1,233 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+	"riddles-server/utils"
+)
+
+// Ledger entry reasons
+const (
+	PointsReasonSolve      = "solve"
+	PointsReasonAdjustment = "adjustment"
+)
+
+// Base points per difficulty before multipliers
+var difficultyPoints = map[string]int{
+	"easy":   10,
+	"medium": 20,
+	"hard":   30,
+}
+
+// quickSolveTime is how fast a riddle must be solved after it was opened to earn the speed bonus
+const quickSolveTime = time.Minute
+
+type Level struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	MinXP  int    `json:"min_xp"`
+}
+
+var levels = []Level{
+	{Number: 1, Name: "Новичок", MinXP: 0},
+	{Number: 2, Name: "Любитель", MinXP: 100},
+	{Number: 3, Name: "Знаток", MinXP: 300},
+	{Number: 4, Name: "Эрудит", MinXP: 700},
+	{Number: 5, Name: "Мастер", MinXP: 1500},
+	{Number: 6, Name: "Мудрец", MinXP: 3000},
+	{Number: 7, Name: "Сфинкс", MinXP: 6000},
+}
+
+type ScoringService interface {
+	AwardSolve(progress *models.UserRiddleProgress, riddle *models.Riddle) error
+	GetScore(userID uint) (*ScoreInfo, error)
+	GetLedger(userID uint) ([]models.PointsEntry, error)
+	Recompute(userID uint) (int, error) // appends adjustments so the ledger matches the current rules, returns the number added
+}
+
+type ScoreInfo struct {
+	XP          int    `json:"xp"`
+	Level       int    `json:"level"`
+	LevelName   string `json:"level_name"`
+	LevelXP     int    `json:"level_xp"`      // XP at which the current level starts
+	NextLevelXP int    `json:"next_level_xp"` // 0 at the top level
+}
+
+// ScoreBreakdown explains how the points for a solve were calculated
+type ScoreBreakdown struct {
+	Difficulty   string  `json:"difficulty"`
+	Base         int     `json:"base"`
+	Attempts     int     `json:"attempts"`
+	HintsUsed    int     `json:"hints_used"`
+	SolveSeconds int     `json:"solve_seconds"`
+	Daily        bool    `json:"daily"`
+	Multiplier   float64 `json:"multiplier"`
+	Points       int     `json:"points"`
+}
+
+type scoringService struct {
+	pointsRepo      repository.PointsRepository
+	progressRepo    repository.ProgressRepository
+	riddleRepo      repository.RiddleRepository
+	dailyRiddleRepo repository.DailyRiddleRepository
+}
+
+func NewScoringService(
+	pointsRepo repository.PointsRepository,
+	progressRepo repository.ProgressRepository,
+	riddleRepo repository.RiddleRepository,
+	dailyRiddleRepo repository.DailyRiddleRepository,
+) ScoringService {
+	return &scoringService{
+		pointsRepo:      pointsRepo,
+		progressRepo:    progressRepo,
+		riddleRepo:      riddleRepo,
+		dailyRiddleRepo: dailyRiddleRepo,
+	}
+}
+
+func (s *scoringService) AwardSolve(progress *models.UserRiddleProgress, riddle *models.Riddle) error {
+	breakdown, err := s.scoreSolve(progress, riddle)
+	if err != nil {
+		return err
+	}
+	return s.appendEntry(progress.UserID, riddle.ID, PointsReasonSolve, breakdown.Points, breakdown)
+}
+
+func (s *scoringService) GetScore(userID uint) (*ScoreInfo, error) {
+	xp, err := s.pointsRepo.GetUserTotal(userID)
+	if err != nil {
+		return nil, err
+	}
+	return NewScoreInfo(xp), nil
+}
+
+func (s *scoringService) GetLedger(userID uint) ([]models.PointsEntry, error) {
+	return s.pointsRepo.FindByUserID(userID)
+}
+
+func (s *scoringService) Recompute(userID uint) (int, error) {
+	progress, err := s.progressRepo.FindByUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	totals, err := s.pointsRepo.GetUserRiddleTotals(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for i := range progress {
+		if !progress[i].Solved {
+			continue
+		}
+
+		riddle, err := s.riddleRepo.FindByID(progress[i].RiddleID)
+		if err != nil {
+			return added, err
+		}
+
+		breakdown, err := s.scoreSolve(&progress[i], riddle)
+		if err != nil {
+			return added, err
+		}
+		diff := breakdown.Points - totals[riddle.ID]
+		if diff == 0 {
+			continue
+		}
+
+		if err := s.appendEntry(userID, riddle.ID, PointsReasonAdjustment, diff, breakdown); err != nil {
+			return added, err
+		}
+		added++
+	}
+
+	return added, nil
+}
+
+func (s *scoringService) scoreSolve(progress *models.UserRiddleProgress, riddle *models.Riddle) (ScoreBreakdown, error) {
+	daily, err := s.dailyRiddleRepo.IsFeatured(riddle.ID, utils.GameDate(progress.SolvedAt))
+	if err != nil {
+		return ScoreBreakdown{}, err
+	}
+
+	breakdown := ScoreBreakdown{
+		Difficulty: riddle.Difficulty,
+		Base:       basePoints(riddle.Difficulty),
+		Attempts:   progress.Attempts,
+		HintsUsed:  progress.HintsUsed,
+		Daily:      daily,
+	}
+	// Progress recorded before attempts were counted was solved at least once
+	if breakdown.Attempts < 1 {
+		breakdown.Attempts = 1
+	}
+
+	multiplier := math.Max(0.5, 1-0.1*float64(breakdown.Attempts-1))
+	multiplier *= math.Max(0.25, 1-0.25*float64(breakdown.HintsUsed))
+
+	// Time is measured from when the riddle was opened; solves without a known
+	// start get no time bonus
+	if progress.StartedAt != nil {
+		solveTime := progress.SolvedAt.Sub(*progress.StartedAt)
+		if solveTime < 0 {
+			solveTime = 0
+		}
+		breakdown.SolveSeconds = int(solveTime.Seconds())
+		if solveTime <= quickSolveTime {
+			multiplier *= 1.2
+		}
+	}
+	if breakdown.Daily {
+		multiplier *= 1.5
+	}
+
+	breakdown.Multiplier = math.Round(multiplier*100) / 100
+	breakdown.Points = int(math.Max(1, math.Round(float64(breakdown.Base)*multiplier)))
+	return breakdown, nil
+}
+
+func (s *scoringService) appendEntry(userID, riddleID uint, reason string, points int, breakdown ScoreBreakdown) error {
+	details, err := json.Marshal(breakdown)
+	if err != nil {
+		return err
+	}
+
+	return s.pointsRepo.Create(&models.PointsEntry{
+		UserID:   userID,
+		RiddleID: &riddleID,
+		Reason:   reason,
+		Points:   points,
+		Details:  string(details),
+	})
+}
+
+// basePoints returns the points for a solve before multipliers
+func basePoints(difficulty string) int {
+	if points, ok := difficultyPoints[difficulty]; ok {
+		return points
+	}
+	return difficultyPoints["easy"]
+}
+
+// NewScoreInfo resolves the level for the given amount of XP
+func NewScoreInfo(xp int) *ScoreInfo {
+	info := &ScoreInfo{XP: xp}
+	for i, level := range levels {
+		if xp < level.MinXP {
+			break
+		}
+		info.Level = level.Number
+		info.LevelName = level.Name
+		info.LevelXP = level.MinXP
+		info.NextLevelXP = 0
+		if i+1 < len(levels) {
+			info.NextLevelXP = levels[i+1].MinXP
+		}
+	}
+	return info
+}
\ No newline at end of file
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+// Marks stored in share tokens, one per riddle of the daily set
+const (
+	shareMarkSolved    = 'S'
+	shareMarkFailed    = 'F'
+	shareMarkUntouched = 'N'
+)
+
+var shareMarkEmoji = map[rune]string{
+	shareMarkSolved:    "🟩",
+	shareMarkFailed:    "🟥",
+	shareMarkUntouched: "⬜",
+}
+
+var (
+	ErrInvalidShareToken = errors.New("invalid share token")
+	ErrNoDailyRiddles    = errors.New("no daily riddles for this date")
+)
+
+type ShareService interface {
+	BuildDailyShare(userID uint, date time.Time) (*DailyShare, error)
+	ParseShareToken(token string) (*DailyShare, error)
+}
+
+// DailyShare is a spoiler-free summary of a user's results for a daily set
+type DailyShare struct {
+	Date      string `json:"date"`
+	Solved    int    `json:"solved"`
+	Total     int    `json:"total"`
+	Grid      string `json:"grid"`
+	HintsUsed int    `json:"hints_used"`
+	Text      string `json:"text"`
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+}
+
+type shareService struct {
+	dailyRiddleRepo repository.DailyRiddleRepository
+	progressRepo    repository.ProgressRepository
+	secret          string
+	baseURL         string
+}
+
+func NewShareService(
+	dailyRiddleRepo repository.DailyRiddleRepository,
+	progressRepo repository.ProgressRepository,
+	secret string,
+	baseURL string,
+) ShareService {
+	return &shareService{
+		dailyRiddleRepo: dailyRiddleRepo,
+		progressRepo:    progressRepo,
+		secret:          secret,
+		baseURL:         strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (s *shareService) BuildDailyShare(userID uint, date time.Time) (*DailyShare, error) {
+	dailyRiddles, err := s.dailyRiddleRepo.FindAllByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if len(dailyRiddles) == 0 {
+		return nil, ErrNoDailyRiddles
+	}
+
+	marks := make([]rune, len(dailyRiddles))
+	hints := 0
+	for i, dailyRiddle := range dailyRiddles {
+		marks[i] = shareMarkUntouched
+
+		progress, err := s.progressRepo.FindByUserAndRiddle(userID, dailyRiddle.RiddleID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		hints += progress.HintsUsed
+		// A reveal without answering counts as failed, not as untouched
+		if progress.Solved {
+			marks[i] = shareMarkSolved
+		} else if progress.Attempts > 0 || progress.Revealed {
+			marks[i] = shareMarkFailed
+		}
+	}
+
+	return s.newShare(date.Format("2006-01-02"), string(marks), hints), nil
+}
+
+func (s *shareService) ParseShareToken(token string) (*DailyShare, error) {
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidShareToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return nil, ErrInvalidShareToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, s.sign(payload)) {
+		return nil, ErrInvalidShareToken
+	}
+
+	// Payload format: YYYY-MM-DD:marks:hints
+	fields := strings.Split(string(payload), ":")
+	if len(fields) != 3 {
+		return nil, ErrInvalidShareToken
+	}
+	if _, err := time.Parse("2006-01-02", fields[0]); err != nil {
+		return nil, ErrInvalidShareToken
+	}
+	for _, mark := range fields[1] {
+		if _, ok := shareMarkEmoji[mark]; !ok {
+			return nil, ErrInvalidShareToken
+		}
+	}
+	hints, err := strconv.Atoi(fields[2])
+	if err != nil || hints < 0 {
+		return nil, ErrInvalidShareToken
+	}
+
+	return s.newShare(fields[0], fields[1], hints), nil
+}
+
+func (s *shareService) newShare(date, marks string, hints int) *DailyShare {
+	share := &DailyShare{
+		Date:      date,
+		Total:     len(marks),
+		HintsUsed: hints,
+	}
+
+	var grid strings.Builder
+	for _, mark := range marks {
+		if mark == shareMarkSolved {
+			share.Solved++
+		}
+		grid.WriteString(shareMarkEmoji[mark])
+	}
+	share.Grid = grid.String()
+
+	share.Text = fmt.Sprintf("Загадки дня %s: %d/%d %s", share.Date, share.Solved, share.Total, share.Grid)
+	if hints == 1 {
+		share.Text += ", 1 hint"
+	} else {
+		share.Text += fmt.Sprintf(", %d hints", hints)
+	}
+
+	payload := []byte(fmt.Sprintf("%s:%s:%d", date, marks, hints))
+	share.Token = base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+	share.URL = s.baseURL + "/share/" + share.Token
+
+	return share
+}
+
+// sign returns a truncated HMAC of the payload; it only has to make tokens unforgeable, not secret
+func (s *shareService) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:12]
+}
\ No newline at end of file
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+	"riddles-server/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// A freeze token is earned for every StreakFreezeEvery consecutive days
+	StreakFreezeEvery = 7
+	// MaxFreezeTokens caps how many freeze tokens a player can hold at once
+	MaxFreezeTokens = 2
+)
+
+type StreakService interface {
+	GetStreak(userID uint) (*StreakInfo, error)
+	RecordDailySolve(userID, riddleID uint, solvedAt time.Time) error
+}
+
+type StreakInfo struct {
+	CurrentStreak  int    `json:"current_streak"`
+	LongestStreak  int    `json:"longest_streak"`
+	FreezeTokens   int    `json:"freeze_tokens"`
+	LastActiveDate string `json:"last_active_date,omitempty"` // YYYY-MM-DD in the game timezone
+	ActiveToday    bool   `json:"active_today"`
+	Timezone       string `json:"timezone"`
+}
+
+type streakService struct {
+	streakRepo      repository.StreakRepository
+	dailyRiddleRepo repository.DailyRiddleRepository
+}
+
+func NewStreakService(streakRepo repository.StreakRepository, dailyRiddleRepo repository.DailyRiddleRepository) StreakService {
+	return &streakService{
+		streakRepo:      streakRepo,
+		dailyRiddleRepo: dailyRiddleRepo,
+	}
+}
+
+func (s *streakService) GetStreak(userID uint) (*StreakInfo, error) {
+	today := utils.GameToday()
+	info := &StreakInfo{
+		Timezone: today.Location().String(),
+	}
+
+	streak, err := s.streakRepo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return info, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info.LongestStreak = streak.LongestStreak
+	info.FreezeTokens = streak.FreezeTokens
+	info.CurrentStreak = streak.CurrentStreak
+	if streak.LastActiveDate != nil {
+		info.LastActiveDate = streak.LastActiveDate.Format("2006-01-02")
+
+		// Today isn't missed yet, so only the days in between count against the streak
+		days := utils.DaysBetween(*streak.LastActiveDate, today)
+		info.ActiveToday = days == 0
+		if days-1 > streak.FreezeTokens {
+			info.CurrentStreak = 0
+		}
+	}
+
+	return info, nil
+}
+
+func (s *streakService) RecordDailySolve(userID, riddleID uint, solvedAt time.Time) error {
+	day := utils.GameDate(solvedAt)
+
+	// Only riddles from the daily set of the day they were solved on count
+	featured, err := s.dailyRiddleRepo.IsFeatured(riddleID, day)
+	if err != nil {
+		return err
+	}
+	if !featured {
+		return nil
+	}
+
+	streak, err := s.streakRepo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		streak = &models.UserStreak{UserID: userID}
+	} else if err != nil {
+		return err
+	}
+
+	if !applyStreakDay(streak, day) {
+		return nil
+	}
+
+	return s.streakRepo.Save(streak)
+}
+
+// applyStreakDay advances the streak to the given game day, spending freeze
+// tokens on missed days when there are enough of them. It reports whether the
+// streak changed.
+func applyStreakDay(streak *models.UserStreak, day time.Time) bool {
+	if streak.LastActiveDate == nil {
+		streak.CurrentStreak = 1
+	} else {
+		missed := utils.DaysBetween(*streak.LastActiveDate, day) - 1
+		switch {
+		case missed < 0:
+			// Already counted (or a late solve for an earlier day)
+			return false
+		case missed == 0:
+			streak.CurrentStreak++
+		case missed <= streak.FreezeTokens:
+			streak.FreezeTokens -= missed
+			streak.CurrentStreak++
+		default:
+			streak.CurrentStreak = 1
+		}
+	}
+
+	if streak.CurrentStreak > streak.LongestStreak {
+		streak.LongestStreak = streak.CurrentStreak
+	}
+	if streak.CurrentStreak%StreakFreezeEvery == 0 && streak.FreezeTokens < MaxFreezeTokens {
+		streak.FreezeTokens++
+	}
+
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	streak.LastActiveDate = &date
+	return true
+}
\ No newline at end of file
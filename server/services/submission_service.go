@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRiddleInvalid           = errors.New("invalid riddle")
+	ErrSubmissionNotFound      = errors.New("submission not found")
+	ErrSubmissionReviewed      = errors.New("submission has already been reviewed")
+	ErrRejectionReasonRequired = errors.New("a rejection reason is required")
+)
+
+// Difficulties lists the valid riddle difficulties, easiest first
+var Difficulties = []string{"easy", "medium", "hard"}
+
+type SubmissionService interface {
+	Submit(userID uint, input RiddleInput) (*models.RiddleSubmission, error)
+	GetUserSubmissions(userID uint) ([]models.RiddleSubmission, error)
+	GetQueue(status string) ([]ModerationItem, error)
+	Approve(moderatorID, submissionID uint, edits RiddleInput) (*models.Riddle, error)
+	Reject(moderatorID, submissionID uint, reason string) (*models.RiddleSubmission, error)
+}
+
+// RiddleInput is the editable content of a riddle
+type RiddleInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Answer      string `json:"answer"`
+	Explanation string `json:"explanation"`
+	CategoryID  uint   `json:"category_id"`
+	Difficulty  string `json:"difficulty"`
+}
+
+type ModerationItem struct {
+	models.RiddleSubmission
+	AuthorName string `json:"author_name"`
+}
+
+type submissionService struct {
+	submissionRepo repository.SubmissionRepository
+	categoryRepo   repository.CategoryRepository
+}
+
+func NewSubmissionService(submissionRepo repository.SubmissionRepository, categoryRepo repository.CategoryRepository) SubmissionService {
+	return &submissionService{
+		submissionRepo: submissionRepo,
+		categoryRepo:   categoryRepo,
+	}
+}
+
+func (s *submissionService) Submit(userID uint, input RiddleInput) (*models.RiddleSubmission, error) {
+	input = input.trimmed()
+	if err := validateRiddle(s.categoryRepo, input, textFormat); err != nil {
+		return nil, err
+	}
+
+	submission := &models.RiddleSubmission{
+		AuthorID:    userID,
+		Title:       input.Title,
+		Description: input.Description,
+		Answer:      input.Answer,
+		Explanation: input.Explanation,
+		CategoryID:  input.CategoryID,
+		Difficulty:  input.Difficulty,
+		Status:      models.SubmissionPending,
+	}
+	if err := s.submissionRepo.Create(submission); err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+func (s *submissionService) GetUserSubmissions(userID uint) ([]models.RiddleSubmission, error) {
+	return s.submissionRepo.FindByAuthor(userID)
+}
+
+func (s *submissionService) GetQueue(status string) ([]ModerationItem, error) {
+	if status == "" {
+		status = models.SubmissionPending
+	}
+
+	submissions, err := s.submissionRepo.FindByStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]ModerationItem, len(submissions))
+	for i, submission := range submissions {
+		items[i] = ModerationItem{RiddleSubmission: submission, AuthorName: submission.Author.Username}
+	}
+	return items, nil
+}
+
+// Approve publishes the submission as a riddle credited to its author. Fields
+// set in edits replace the submitted ones before publishing.
+func (s *submissionService) Approve(moderatorID, submissionID uint, edits RiddleInput) (*models.Riddle, error) {
+	submission, err := s.findPending(submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := edits.trimmed().over(RiddleInput{
+		Title:       submission.Title,
+		Description: submission.Description,
+		Answer:      submission.Answer,
+		Explanation: submission.Explanation,
+		CategoryID:  submission.CategoryID,
+		Difficulty:  submission.Difficulty,
+	})
+	if err := validateRiddle(s.categoryRepo, input, textFormat); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	submission.Title = input.Title
+	submission.Description = input.Description
+	submission.Answer = input.Answer
+	submission.Explanation = input.Explanation
+	submission.CategoryID = input.CategoryID
+	submission.Difficulty = input.Difficulty
+	submission.Status = models.SubmissionApproved
+	submission.ModeratorID = &moderatorID
+	submission.ReviewedAt = &now
+
+	riddle := &models.Riddle{
+		Title:       input.Title,
+		Description: input.Description,
+		Answer:      input.Answer,
+		Explanation: input.Explanation,
+		CategoryID:  input.CategoryID,
+		Difficulty:  input.Difficulty,
+		AuthorID:    &submission.AuthorID,
+		AuthorName:  submission.Author.Username,
+	}
+	approved, err := s.submissionRepo.Approve(submission, riddle)
+	if err != nil {
+		return nil, err
+	}
+	if !approved {
+		return nil, ErrSubmissionReviewed
+	}
+	return riddle, nil
+}
+
+func (s *submissionService) Reject(moderatorID, submissionID uint, reason string) (*models.RiddleSubmission, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	submission, err := s.findPending(submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	submission.Status = models.SubmissionRejected
+	submission.ModeratorID = &moderatorID
+	submission.RejectionReason = reason
+	submission.ReviewedAt = &now
+
+	rejected, err := s.submissionRepo.Reject(submission)
+	if err != nil {
+		return nil, err
+	}
+	if !rejected {
+		return nil, ErrSubmissionReviewed
+	}
+	return submission, nil
+}
+
+func (s *submissionService) findPending(submissionID uint) (*models.RiddleSubmission, error) {
+	submission, err := s.submissionRepo.FindByID(submissionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	if submission.Status != models.SubmissionPending {
+		return nil, ErrSubmissionReviewed
+	}
+	return submission, nil
+}
+
+// validateRiddle checks the input and that its category exists
+func validateRiddle(categoryRepo repository.CategoryRepository, input RiddleInput, format RiddleFormat) error {
+	if err := input.check(format); err != nil {
+		return err
+	}
+
+	if _, err := categoryRepo.FindByID(input.CategoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: category not found", ErrRiddleInvalid)
+		}
+		return err
+	}
+	return nil
+}
+
+// check validates the fields that don't need the database; only text
+// riddles have an answer
+func (input RiddleInput) check(format RiddleFormat) error {
+	switch {
+	case input.Title == "":
+		return fmt.Errorf("%w: title is required", ErrRiddleInvalid)
+	case len([]rune(input.Title)) > 255:
+		return fmt.Errorf("%w: title is too long", ErrRiddleInvalid)
+	case input.Description == "":
+		return fmt.Errorf("%w: description is required", ErrRiddleInvalid)
+	case input.Answer == "" && format.Type == models.RiddleText:
+		return fmt.Errorf("%w: answer is required", ErrRiddleInvalid)
+	case input.Answer != "" && format.Type != models.RiddleText:
+		return fmt.Errorf("%w: only text riddles have an answer; put the solution in the key", ErrRiddleInvalid)
+	case !slices.Contains(Difficulties, input.Difficulty):
+		return fmt.Errorf("%w: difficulty must be easy, medium or hard", ErrRiddleInvalid)
+	}
+	return format.check()
+}
+
+func (input RiddleInput) trimmed() RiddleInput {
+	input.Title = strings.TrimSpace(input.Title)
+	input.Description = strings.TrimSpace(input.Description)
+	input.Answer = strings.TrimSpace(input.Answer)
+	input.Explanation = strings.TrimSpace(input.Explanation)
+	input.Difficulty = strings.ToLower(strings.TrimSpace(input.Difficulty))
+	return input
+}
+
+// over returns base with the fields set in input replacing its own
+func (input RiddleInput) over(base RiddleInput) RiddleInput {
+	if input.Title != "" {
+		base.Title = input.Title
+	}
+	if input.Description != "" {
+		base.Description = input.Description
+	}
+	if input.Answer != "" {
+		base.Answer = input.Answer
+	}
+	if input.Explanation != "" {
+		base.Explanation = input.Explanation
+	}
+	if input.CategoryID != 0 {
+		base.CategoryID = input.CategoryID
+	}
+	if input.Difficulty != "" {
+		base.Difficulty = input.Difficulty
+	}
+	return base
+}
\ No newline at end of file
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TagMaxLength     = 50
+	MaxTagsPerRiddle = 10
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagInvalid  = errors.New("tag must be 1-50 characters")
+	ErrTagExists   = errors.New("tag already exists")
+	ErrTooManyTags = errors.New("a riddle can have at most 10 tags")
+)
+
+type TagService interface {
+	GetTags() ([]repository.TagCount, error)
+	CreateTag(name string) (*models.Tag, error)
+	RenameTag(tagID uint, name string) (*models.Tag, error)
+	DeleteTag(tagID uint) error
+	SetRiddleTags(riddleID uint, names []string) ([]models.Tag, error)
+	ResolveTags(names []string) ([]models.Tag, error) // finds or creates tags by name, without duplicates
+}
+
+type tagService struct {
+	tagRepo    repository.TagRepository
+	riddleRepo repository.RiddleRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository, riddleRepo repository.RiddleRepository) TagService {
+	return &tagService{
+		tagRepo:    tagRepo,
+		riddleRepo: riddleRepo,
+	}
+}
+
+func (s *tagService) GetTags() ([]repository.TagCount, error) {
+	return s.tagRepo.FindAllWithCounts()
+}
+
+func (s *tagService) CreateTag(name string) (*models.Tag, error) {
+	tag, err := newTag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.tagRepo.FindByKey(tag.Key); err == nil {
+		return nil, ErrTagExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.tagRepo.FindOrCreate(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// RenameTag changes the tag's name; the new name may only differ from
+// another tag's by case
+func (s *tagService) RenameTag(tagID uint, name string) (*models.Tag, error) {
+	renamed, err := newTag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	existing, err := s.tagRepo.FindByKey(renamed.Key)
+	if err == nil && existing.ID != tag.ID {
+		return nil, ErrTagExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	tag.Name = renamed.Name
+	tag.Key = renamed.Key
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) DeleteTag(tagID uint) error {
+	if _, err := s.tagRepo.FindByID(tagID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTagNotFound
+		}
+		return err
+	}
+	return s.tagRepo.Delete(tagID)
+}
+
+func (s *tagService) SetRiddleTags(riddleID uint, names []string) ([]models.Tag, error) {
+	if _, err := s.riddleRepo.FindByID(riddleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRiddleNotFound
+		}
+		return nil, err
+	}
+
+	tags, err := s.ResolveTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.ReplaceRiddleTags(riddleID, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (s *tagService) ResolveTags(names []string) ([]models.Tag, error) {
+	tags, err := resolveTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tags {
+		if err := s.tagRepo.FindOrCreate(&tags[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// resolveTagNames turns names into unsaved tags, dropping duplicates
+func resolveTagNames(names []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		tag, err := newTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[tag.Key] {
+			continue
+		}
+		seen[tag.Key] = true
+		tags = append(tags, *tag)
+	}
+	if len(tags) > MaxTagsPerRiddle {
+		return nil, ErrTooManyTags
+	}
+	return tags, nil
+}
+
+// newTag tidies up the spacing of the name and derives its key
+func newTag(name string) (*models.Tag, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || len([]rune(name)) > TagMaxLength {
+		return nil, ErrTagInvalid
+	}
+	return &models.Tag{Name: name, Key: tagKey(name)}, nil
+}
+
+// tagKey normalizes a tag name: lowercase, ё as е, single spaces
+func tagKey(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "ё", "е")
+	return strings.Join(strings.Fields(name), " ")
+}
\ No newline at end of file
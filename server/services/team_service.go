@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTeamNotFound       = errors.New("team not found")
+	ErrTeamNameTaken      = errors.New("team name is already taken")
+	ErrTeamNameInvalid    = errors.New("team name must be 2-100 characters")
+	ErrNotTeamCaptain     = errors.New("only the captain can manage the team")
+	ErrTeamMemberExists   = errors.New("user is already a team member")
+	ErrTeamMemberNotFound = errors.New("user is not a team member")
+	ErrCaptainCannotLeave = errors.New("captain must hand over the team before leaving")
+	ErrTeamUserNotFound   = errors.New("user not found")
+)
+
+type TeamService interface {
+	CreateTeam(userID uint, name string) (*TeamView, error)
+	GetTeam(teamID uint) (*TeamView, error)
+	GetUserTeams(userID uint) ([]TeamView, error)
+	AddMember(userID, teamID, memberID uint) (*TeamView, error)
+	RemoveMember(userID, teamID, memberID uint) (*TeamView, error)
+	SetCaptain(userID, teamID, memberID uint) (*TeamView, error)
+}
+
+type TeamView struct {
+	ID        uint             `json:"id"`
+	Name      string           `json:"name"`
+	CaptainID uint             `json:"captain_id"`
+	Members   []TeamMemberView `json:"members"`
+}
+
+type TeamMemberView struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Captain  bool   `json:"captain"`
+}
+
+type teamService struct {
+	teamRepo repository.TeamRepository
+	userRepo repository.UserRepository
+}
+
+func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.UserRepository) TeamService {
+	return &teamService{
+		teamRepo: teamRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *teamService) CreateTeam(userID uint, name string) (*TeamView, error) {
+	name = strings.TrimSpace(name)
+	if length := len([]rune(name)); length < 2 || length > 100 {
+		return nil, ErrTeamNameInvalid
+	}
+
+	if _, err := s.teamRepo.FindByName(name); err == nil {
+		return nil, ErrTeamNameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	team := &models.Team{
+		Name:      name,
+		CaptainID: userID,
+		Members:   []models.TeamMember{{UserID: userID}},
+	}
+	if err := s.teamRepo.Create(team); err != nil {
+		return nil, err
+	}
+
+	return s.GetTeam(team.ID)
+}
+
+func (s *teamService) GetTeam(teamID uint) (*TeamView, error) {
+	team, err := s.findTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return newTeamView(team), nil
+}
+
+func (s *teamService) GetUserTeams(userID uint) ([]TeamView, error) {
+	teams, err := s.teamRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]TeamView, 0, len(teams))
+	for i := range teams {
+		views = append(views, *newTeamView(&teams[i]))
+	}
+	return views, nil
+}
+
+func (s *teamService) AddMember(userID, teamID, memberID uint) (*TeamView, error) {
+	if _, err := s.captainTeam(userID, teamID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.FindByID(memberID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamUserNotFound
+		}
+		return nil, err
+	}
+
+	// Registration checks that nobody plays for two teams in one game; a
+	// player added later must not break that either
+	rival, err := s.teamRepo.PlaysForRival(teamID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if rival {
+		return nil, ErrChgkPlayerRegistered
+	}
+
+	added, err := s.teamRepo.AddMember(&models.TeamMember{TeamID: teamID, UserID: memberID})
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrTeamMemberExists
+	}
+
+	return s.GetTeam(teamID)
+}
+
+// RemoveMember lets the captain drop a player and any player leave on their own
+func (s *teamService) RemoveMember(userID, teamID, memberID uint) (*TeamView, error) {
+	team, err := s.findTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != memberID && team.CaptainID != userID {
+		return nil, ErrNotTeamCaptain
+	}
+	if memberID == team.CaptainID {
+		return nil, ErrCaptainCannotLeave
+	}
+
+	removed, err := s.teamRepo.RemoveMember(teamID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, ErrTeamMemberNotFound
+	}
+
+	return s.GetTeam(teamID)
+}
+
+func (s *teamService) SetCaptain(userID, teamID, memberID uint) (*TeamView, error) {
+	team, err := s.captainTeam(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !isTeamMember(team, memberID) {
+		return nil, ErrTeamMemberNotFound
+	}
+
+	if err := s.teamRepo.SetCaptain(teamID, memberID); err != nil {
+		return nil, err
+	}
+
+	return s.GetTeam(teamID)
+}
+
+func (s *teamService) findTeam(teamID uint) (*models.Team, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) captainTeam(userID, teamID uint) (*models.Team, error) {
+	team, err := s.findTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.CaptainID != userID {
+		return nil, ErrNotTeamCaptain
+	}
+	return team, nil
+}
+
+func isTeamMember(team *models.Team, userID uint) bool {
+	for _, member := range team.Members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func newTeamView(team *models.Team) *TeamView {
+	view := &TeamView{
+		ID:        team.ID,
+		Name:      team.Name,
+		CaptainID: team.CaptainID,
+		Members:   make([]TeamMemberView, 0, len(team.Members)),
+	}
+	for _, member := range team.Members {
+		view.Members = append(view.Members, TeamMemberView{
+			UserID:   member.UserID,
+			Username: member.User.Username,
+			Captain:  member.UserID == team.CaptainID,
+		})
+	}
+	return view
+}
\ No newline at end of file
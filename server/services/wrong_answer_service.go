@@ -0,0 +1,284 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"riddles-server/models"
+	"riddles-server/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultWrongAnswersLimit = 20
+	MaxWrongAnswersLimit     = 100
+)
+
+var (
+	ErrWrongAnswerInvalid = errors.New("answer is required")
+	ErrAnswerAccepted     = errors.New("answer is already accepted")
+	ErrPromotionNotText   = errors.New("only free-text riddles take extra answers")
+)
+
+type WrongAnswerService interface {
+	HandleEvent(event Event) error
+	GetTopWrongAnswers(riddleID uint, limit int) (*WrongAnswerReport, error)
+	Promote(editorID, riddleID uint, answer string) (*Promotion, error)
+}
+
+type WrongAnswerReport struct {
+	RiddleID        uint               `json:"riddle_id"`
+	Answer          string             `json:"answer"`
+	AcceptedAnswers []string           `json:"accepted_answers"` // including the answer
+	WrongAnswers    []WrongAnswerEntry `json:"wrong_answers"`
+}
+
+type WrongAnswerEntry struct {
+	Answer          string    `json:"answer"` // normalized
+	Example         string    `json:"example"`
+	Submissions     int       `json:"submissions"`
+	Players         int       `json:"players"`
+	Distance        int       `json:"distance"` // edit distance to the closest accepted answer
+	LastSubmittedAt time.Time `json:"last_submitted_at"`
+}
+
+// Promotion is the outcome of accepting a wrong answer
+type Promotion struct {
+	Answer          string   `json:"answer"`
+	AcceptedAnswers []string `json:"accepted_answers"`
+	CreditedPlayers int      `json:"credited_players"`
+	FailedCredits   int      `json:"failed_credits"` // players whose solve couldn't be recorded; see the server log
+}
+
+type wrongAnswerService struct {
+	wrongAnswerRepo repository.WrongAnswerRepository
+	riddleRepo      repository.RiddleRepository
+	progressRepo    repository.ProgressRepository
+	editorService   EditorService
+	events          EventBus
+}
+
+func NewWrongAnswerService(
+	wrongAnswerRepo repository.WrongAnswerRepository,
+	riddleRepo repository.RiddleRepository,
+	progressRepo repository.ProgressRepository,
+	editorService EditorService,
+	events EventBus,
+) WrongAnswerService {
+	return &wrongAnswerService{
+		wrongAnswerRepo: wrongAnswerRepo,
+		riddleRepo:      riddleRepo,
+		progressRepo:    progressRepo,
+		editorService:   editorService,
+		events:          events,
+	}
+}
+
+// HandleEvent records wrong answers
+func (s *wrongAnswerService) HandleEvent(event Event) error {
+	if event.Type != EventAnswerWrong {
+		return nil
+	}
+
+	normalized := normalizeAnswer(event.Answer)
+	if normalized == "" {
+		return nil
+	}
+
+	return s.wrongAnswerRepo.Create(&models.WrongAnswer{
+		RiddleID:   event.Riddle.ID,
+		UserID:     event.UserID,
+		Answer:     strings.TrimSpace(event.Answer),
+		Normalized: normalized,
+		Attempt:    event.Progress.Attempts,
+		CreatedAt:  event.At,
+	})
+}
+
+// GetTopWrongAnswers lists the wrong answers given by the most players.
+// Answers accepted since they were submitted are left out.
+func (s *wrongAnswerService) GetTopWrongAnswers(riddleID uint, limit int) (*WrongAnswerReport, error) {
+	riddle, err := s.findRiddle(riddleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultWrongAnswersLimit
+	}
+	if limit > MaxWrongAnswersLimit {
+		limit = MaxWrongAnswersLimit
+	}
+
+	accepted := acceptedAnswers(riddle)
+	counts, err := s.wrongAnswerRepo.TopForRiddle(riddleID, limit+len(accepted))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &WrongAnswerReport{
+		RiddleID:        riddle.ID,
+		Answer:          SolutionOf(riddle),
+		AcceptedAnswers: accepted,
+		WrongAnswers:    []WrongAnswerEntry{},
+	}
+	for _, count := range counts {
+		if len(report.WrongAnswers) == limit {
+			break
+		}
+		if answerMatches(riddle, count.Normalized) {
+			continue
+		}
+
+		report.WrongAnswers = append(report.WrongAnswers, WrongAnswerEntry{
+			Answer:          count.Normalized,
+			Example:         count.Example,
+			Submissions:     count.Submissions,
+			Players:         count.Players,
+			Distance:        nearMissDistance(count.Normalized, accepted),
+			LastSubmittedAt: count.LastSubmittedAt,
+		})
+	}
+
+	return report, nil
+}
+
+// Promote adds the answer to the riddle's accepted answers, as a new revision,
+// and credits the players who submitted it with a solve at the time of their
+// first such answer
+func (s *wrongAnswerService) Promote(editorID, riddleID uint, answer string) (*Promotion, error) {
+	normalized := normalizeAnswer(answer)
+	if normalized == "" {
+		return nil, ErrWrongAnswerInvalid
+	}
+
+	riddle, err := s.findRiddle(riddleID)
+	if err != nil {
+		return nil, err
+	}
+	if !isTextRiddle(riddle) {
+		return nil, ErrPromotionNotText
+	}
+	if answerMatches(riddle, normalized) {
+		return nil, ErrAnswerAccepted
+	}
+
+	riddle, err = s.editorService.UpdateRiddle(editorID, riddle.ID, RiddleEdit{
+		RiddleInput: RiddleInput{
+			Title:       riddle.Title,
+			Description: riddle.Description,
+			Answer:      riddle.Answer,
+			Explanation: riddle.Explanation,
+			CategoryID:  riddle.CategoryID,
+			Difficulty:  riddle.Difficulty,
+		},
+		RiddleFormat:    textFormat,
+		AcceptedAnswers: append(acceptedAnswers(riddle)[1:], normalized),
+		Version:         riddle.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := s.wrongAnswerRepo.FindFirstPerPlayer(riddle.ID, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	// The answer is accepted at this point, so a failed credit doesn't fail
+	// the promotion; it's counted and logged instead
+	promotion := &Promotion{Answer: normalized, AcceptedAnswers: acceptedAnswers(riddle)}
+	for _, submission := range submissions {
+		credited, err := s.credit(riddle, submission)
+		if err != nil {
+			log.Printf("Failed to credit user %d for riddle %d: %v", submission.UserID, riddle.ID, err)
+			promotion.FailedCredits++
+			continue
+		}
+		if credited {
+			promotion.CreditedPlayers++
+		}
+	}
+
+	return promotion, nil
+}
+
+// credit marks the riddle solved as of the submission, unless the player has
+// solved it since. Attempts after the submission are dropped from the count.
+func (s *wrongAnswerService) credit(riddle *models.Riddle, submission models.WrongAnswer) (bool, error) {
+	progress, err := s.progressRepo.FindByUserAndRiddle(submission.UserID, riddle.ID)
+	if err != nil {
+		return false, err
+	}
+	if progress.Solved {
+		return false, nil
+	}
+
+	progress.Solved = true
+	progress.SolvedAt = submission.CreatedAt
+	if submission.Attempt > 0 && submission.Attempt < progress.Attempts {
+		progress.Attempts = submission.Attempt
+	}
+	if err := s.progressRepo.Update(progress); err != nil {
+		return false, err
+	}
+
+	s.events.Publish(Event{
+		Type:        EventRiddleSolved,
+		UserID:      submission.UserID,
+		Riddle:      riddle,
+		Progress:    progress,
+		Answer:      submission.Answer,
+		At:          submission.CreatedAt,
+		Retroactive: true,
+	})
+	return true, nil
+}
+
+func (s *wrongAnswerService) findRiddle(riddleID uint) (*models.Riddle, error) {
+	riddle, err := s.riddleRepo.FindByID(riddleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRiddleNotFound
+		}
+		return nil, err
+	}
+	return riddle, nil
+}
+
+// nearMissDistance is the edit distance from the answer to the closest accepted one
+func nearMissDistance(answer string, accepted []string) int {
+	best := -1
+	for _, candidate := range accepted {
+		distance := levenshtein(answer, normalizeAnswer(candidate))
+		if best < 0 || distance < best {
+			best = distance
+		}
+	}
+	return best
+}
+
+// levenshtein counts the single-letter insertions, deletions and substitutions between a and b
+func levenshtein(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	previous := make([]int, len(y)+1)
+	current := make([]int, len(y)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		current[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(y)]
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"riddles-server/config"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps uploaded files. Keys are slash-separated paths; clients
+// never get them directly, only time-limited signed URLs.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	Delete(ctx context.Context, key string) error // deleting a missing blob is not an error
+	SignedURL(key string, ttl time.Duration) string
+}
+
+// NewBlobStore builds the store selected by the configuration
+func NewBlobStore(cfg *config.Config) (BlobStore, error) {
+	switch cfg.MediaStore {
+	case "local":
+		return NewLocalStore(cfg.MediaDir, cfg.PublicURL+"/media", cfg.JWTSecret)
+	case "s3":
+		return NewS3Store(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	}
+	return nil, fmt.Errorf("unknown media store %q", cfg.MediaStore)
+}
\ No newline at end of file
@@ -9,21 +9,23 @@ import (
 
 // SelectDailyRiddles selects 6 riddles for the current date
 func SelectDailyRiddles() error {
-	// Get today's date
-	today := time.Now().Truncate(24 * time.Hour)
+	// Get today's date in the game timezone
+	today := GameToday()
 	
 	// Check if we already have daily riddles for today
 	var count int64
-	database.DB.Model(&models.DailyRiddle{}).Where("featured_date = ?", today).Count(&count)
+	if err := database.DB.Model(&models.DailyRiddle{}).Where("featured_date = ?", today.Format("2006-01-02")).Count(&count).Error; err != nil {
+		return err
+	}
 	
 	// If we already have daily riddles for today, don't select new ones
 	if count > 0 {
 		return nil
 	}
 	
-	// Get all riddles
+	// Get all published riddles
 	var riddles []models.Riddle
-	if err := database.DB.Find(&riddles).Error; err != nil {
+	if err := database.DB.Where("status = ?", models.RiddlePublished).Find(&riddles).Error; err != nil {
 		return err
 	}
 	
@@ -55,10 +57,10 @@ func SelectDailyRiddles() error {
 
 // GetTodaysRiddles returns the riddles selected for today
 func GetTodaysRiddles() ([]models.DailyRiddle, error) {
-	today := time.Now().Truncate(24 * time.Hour)
+	today := GameToday()
 	
 	var dailyRiddles []models.DailyRiddle
-	err := database.DB.Preload("Riddle.Category").Where("featured_date = ?", today).Find(&dailyRiddles).Error
+	err := database.DB.Preload("Riddle.Category").Where("featured_date = ?", today.Format("2006-01-02")).Find(&dailyRiddles).Error
 	
 	return dailyRiddles, err
 }
\ No newline at end of file
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"log"
+	"riddles-server/config"
+	"time"
+)
+
+// gameLocation is loaded once at startup; a misconfigured zone stops the
+// server instead of silently shifting game days to UTC
+var gameLocation = loadGameLocation()
+
+func loadGameLocation() *time.Location {
+	loc, err := time.LoadLocation(config.LoadConfig().GameTimezone)
+	if err != nil {
+		log.Fatal("Invalid game timezone:", err)
+	}
+	return loc
+}
+
+// GameLocation returns the timezone in which game days are counted
+func GameLocation() *time.Location {
+	return gameLocation
+}
+
+// GameDate returns midnight of the game day that t falls on
+func GameDate(t time.Time) time.Time {
+	t = t.In(GameLocation())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// GameToday returns midnight of the current game day
+func GameToday() time.Time {
+	return GameDate(time.Now())
+}
+
+// DaysBetween returns the number of calendar days from a to b
+func DaysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
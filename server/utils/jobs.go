@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"log"
+	"time"
+)
+
+// StartJob runs job right away and then every interval in the background
+func StartJob(name string, interval time.Duration, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := job(); err != nil {
+				log.Printf("Job %s failed: %v", name, err)
+			}
+			<-ticker.C
+		}
+	}()
+}
\ No newline at end of file
@@ -17,6 +17,7 @@ func SeedDatabase() {
 	}
 
 	for i := range categories {
+		categories[i].Slug = Slugify(categories[i].Name)
 		err := database.DB.FirstOrCreate(&categories[i], models.Category{Name: categories[i].Name}).Error
 		if err != nil {
 			log.Printf("Error creating category %s: %v", categories[i].Name, err)
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillicLatin transliterates Russian letters for URLs
+var cyrillicLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// Slugify turns a name into a lowercase ASCII URL segment, transliterating
+// Cyrillic: "Что? Где? Когда?" becomes "chto-gde-kogda"
+func Slugify(name string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		latin, ok := cyrillicLatin[r]
+		switch {
+		case ok:
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			latin = string(r)
+		default:
+			// Everything else separates words
+			dash = slug.Len() > 0
+			continue
+		}
+		if latin == "" {
+			continue
+		}
+		if dash {
+			slug.WriteByte('-')
+			dash = false
+		}
+		slug.WriteString(latin)
+	}
+	return slug.String()
+}
\ No newline at end of file